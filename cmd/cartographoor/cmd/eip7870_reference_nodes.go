@@ -6,12 +6,16 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/ethpandaops/cartographoor/pkg/eip7870referencenodes"
+	"github.com/ethpandaops/cartographoor/pkg/githubapi"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
 	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
 )
 
@@ -23,6 +27,9 @@ type eip7870ReferenceNodesConfig struct {
 	Storage               s3.Config                     `mapstructure:"storage"`
 	EIP7870ReferenceNodes *eip7870referencenodes.Config `mapstructure:"eip7870ReferenceNodes"`
 	GitHubToken           string                        `mapstructure:"githubToken"`
+	GitHub                githubapi.Config              `mapstructure:"github"`
+	Metrics               metrics.Config                `mapstructure:"metrics"`
+	HTTPCache             httpcache.Config              `mapstructure:"httpCache"`
 }
 
 func newEIP7870ReferenceNodesCmd(log *logrus.Logger) *cobra.Command {
@@ -119,14 +126,28 @@ func runEIP7870ReferenceNodes(ctx context.Context, log *logrus.Logger, cfg *eip7
 		return fmt.Errorf("failed to initialize storage provider: %w", initErr)
 	}
 
-	// Get GitHub token
-	githubToken := cfg.GitHubToken
-	if githubToken == "" {
-		githubToken = os.Getenv("GITHUB_TOKEN")
+	// Get GitHub credentials, falling back to a token
+	githubConfig := cfg.GitHub
+	if !githubConfig.IsSet() {
+		githubConfig.Token = cfg.GitHubToken
 	}
 
-	if githubToken == "" {
-		log.Warn("No GitHub token configured, rate limits may apply")
+	if !githubConfig.IsSet() {
+		githubConfig.Token = os.Getenv("GITHUB_TOKEN")
+	}
+
+	if !githubConfig.IsSet() {
+		log.Warn("No GitHub token or app configured, rate limits may apply")
+	}
+
+	githubAuth, err := githubapi.NewTokenSource(githubConfig)
+	if err != nil {
+		return err
+	}
+
+	httpCache, err := httpcache.New(cfg.HTTPCache)
+	if err != nil {
+		return fmt.Errorf("failed to create http cache: %w", err)
 	}
 
 	// Create service
@@ -134,13 +155,20 @@ func runEIP7870ReferenceNodes(ctx context.Context, log *logrus.Logger, cfg *eip7
 		log,
 		cfg.EIP7870ReferenceNodes,
 		storageProvider,
-		githubToken,
+		githubAuth,
+		httpCache,
 	)
 
 	log.Info("Starting EIP-7870 reference nodes generation")
 
 	// Generate and upload
-	if err := service.Generate(ctx); err != nil {
+	start := time.Now()
+	err = service.Generate(ctx)
+
+	metrics.ObserveGeneration(metrics.GeneratorEIP7870ReferenceNodes, start, err)
+	pushMetrics(log, cfg.Metrics, "cartographoor_eip7870_reference_nodes")
+
+	if err != nil {
 		return fmt.Errorf("EIP-7870 reference nodes generation failed: %w", err)
 	}
 
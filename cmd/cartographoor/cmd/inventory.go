@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ethpandaops/cartographoor/pkg/inventory"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
 	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -22,6 +23,7 @@ type inventoryConfig struct {
 	ConfigFile string
 	Storage    s3.Config         `mapstructure:"storage"`
 	Inventory  inventorySettings `mapstructure:"inventory"`
+	Metrics    metrics.Config    `mapstructure:"metrics"`
 }
 
 type inventorySettings struct {
@@ -116,7 +118,13 @@ func runInventory(ctx context.Context, log *logrus.Logger, cfg *inventoryConfig)
 	log.Info("Starting inventory generation")
 
 	// Run the inventory generation
-	if err := service.Run(ctx); err != nil {
+	start := time.Now()
+	err = service.Run(ctx)
+
+	metrics.ObserveGeneration(metrics.GeneratorInventory, start, err)
+	pushMetrics(log, cfg.Metrics, "cartographoor_inventory")
+
+	if err != nil {
 		return fmt.Errorf("inventory generation failed: %w", err)
 	}
 
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+)
+
+// pushMetrics pushes the collected metrics to the configured Pushgateway, if
+// any. One-shot commands call it before exiting.
+func pushMetrics(log logrus.FieldLogger, cfg metrics.Config, job string) {
+	if cfg.PushgatewayURL == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := metrics.Push(ctx, &http.Client{Timeout: 10 * time.Second}, cfg.PushgatewayURL, job); err != nil {
+		log.WithError(err).Warn("Failed to push metrics")
+
+		return
+	}
+
+	log.WithField("job", job).Debug("Pushed metrics")
+}
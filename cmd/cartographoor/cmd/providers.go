@@ -0,0 +1,9 @@
+package cmd
+
+// Discovery providers register their factories with the discovery package when
+// imported. Import new providers here to make them available under
+// discovery.providers.
+import (
+	_ "github.com/ethpandaops/cartographoor/pkg/providers/github"
+	_ "github.com/ethpandaops/cartographoor/pkg/providers/static"
+)
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/changelog"
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/ethpandaops/cartographoor/pkg/notify"
+	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
+	"github.com/ethpandaops/cartographoor/pkg/uploadguard"
+)
+
+// resultPublisher uploads discovery results to S3 after checking them against
+// the upload guard and, if enabled, publishes a changelog and sends
+// notifications against the previously published result.
+type resultPublisher struct {
+	log       *logrus.Logger
+	cfg       *runConfig
+	storage   *s3.Provider
+	guard     *uploadguard.Guard
+	changelog *changelog.Publisher
+	notifier  *notify.Notifier
+	// schemaPublished is set once the JSON Schema has been uploaded by this
+	// process.
+	schemaPublished bool
+}
+
+// newResultPublisher creates a result publisher. notifier may be nil.
+func newResultPublisher(log *logrus.Logger, cfg *runConfig, storage *s3.Provider, notifier *notify.Notifier) (*resultPublisher, error) {
+	guard, err := uploadguard.New(cfg.UploadGuard)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &resultPublisher{
+		log:      log,
+		cfg:      cfg,
+		storage:  storage,
+		guard:    guard,
+		notifier: notifier,
+	}
+
+	if cfg.Changelog.Enabled {
+		p.changelog = changelog.NewPublisher(log, cfg.Changelog, storage)
+	}
+
+	return p, nil
+}
+
+// publish uploads the result and its changelog and sends notifications. It
+// returns false if the upload was skipped, in which case nothing is sent.
+func (p *resultPublisher) publish(ctx context.Context, result discovery.Result) (bool, error) {
+	// Skip upload if there are no networks
+	if len(result.Networks) == 0 {
+		p.log.Info("No networks discovered, skipping S3 upload")
+
+		return false, nil
+	}
+
+	if err := checkProviderFailures(p.log, p.cfg, result); err != nil {
+		return false, fmt.Errorf("refusing to upload networks to S3: %w", err)
+	}
+
+	previous, err := p.checkGuard(ctx, result)
+	if err != nil {
+		return false, fmt.Errorf("refusing to upload networks to S3: %w", err)
+	}
+
+	// Upload to S3
+	if err := p.storage.Upload(ctx, result); err != nil {
+		return false, fmt.Errorf("failed to upload networks to S3: %w", err)
+	}
+
+	// The schema only changes with the binary, so it is uploaded once per process.
+	if !p.schemaPublished {
+		if err := p.publishSchema(ctx); err != nil {
+			p.log.WithError(err).Warn("Failed to publish JSON schema")
+		} else {
+			p.schemaPublished = true
+		}
+	}
+
+	// A failed changelog doesn't fail the upload, networks.json is already published.
+	if p.changelog != nil && previous != nil {
+		if err := p.changelog.Publish(ctx, changelog.Diff(*previous, result)); err != nil {
+			p.log.WithError(err).Warn("Failed to publish changelog")
+		}
+	}
+
+	// Notifications describe the published result, so they are only sent
+	// after a successful upload.
+	if p.notifier != nil {
+		if err := p.notifier.Notify(ctx, result); err != nil {
+			p.log.WithError(err).Warn("Failed to send notifications")
+		}
+	}
+
+	return true, nil
+}
+
+// publishSchema uploads the JSON Schema of the discovery result.
+func (p *resultPublisher) publishSchema(ctx context.Context) error {
+	data, err := discovery.SchemaJSON()
+	if err != nil {
+		return err
+	}
+
+	return p.storage.UploadRaw(ctx, p.storage.SchemaKey(), data, "application/schema+json")
+}
+
+// checkGuard compares the result with the currently published one and returns
+// an error if the upload guard blocks it. With --force-upload, violations are
+// logged instead. It returns the published result, or nil if there is none.
+func (p *resultPublisher) checkGuard(ctx context.Context, result discovery.Result) (*discovery.Result, error) {
+	previous, err := loadPublishedResult(ctx, p.storage)
+	if err != nil {
+		if !s3.IsNotFound(err) {
+			if !p.cfg.ForceUpload {
+				return nil, fmt.Errorf("failed to load published networks for the upload guard: %w", err)
+			}
+
+			p.log.WithError(err).Warn("Failed to load published networks, forcing upload")
+		}
+
+		previous = nil
+	}
+
+	if err := p.guard.Check(previous, result); err != nil {
+		if !p.cfg.ForceUpload {
+			return nil, err
+		}
+
+		p.log.WithError(err).Warn("Upload guard violated, forcing upload")
+	}
+
+	return previous, nil
+}
+
+// checkProviderFailures logs any failed providers and returns an error if the
+// result should not be uploaded because of them.
+func checkProviderFailures(log *logrus.Logger, cfg *runConfig, result discovery.Result) error {
+	failed := result.FailedProviders()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(failed))
+
+	for _, p := range failed {
+		names = append(names, p.Name)
+
+		log.WithFields(logrus.Fields{
+			"provider": p.Name,
+			"error":    p.Error,
+		}).Warn("Discovery provider failed, result is partial")
+	}
+
+	if cfg.SkipUploadOnProviderFailure {
+		return fmt.Errorf("discovery providers failed: %s", strings.Join(names, ", "))
+	}
+
+	return nil
+}
@@ -14,6 +14,8 @@ func NewRootCommand(log *logrus.Logger) *cobra.Command {
 
 	// Add subcommands.
 	cmd.AddCommand(newRunCmd(log))
+	cmd.AddCommand(newServeCmd(log))
+	cmd.AddCommand(newSchemaCmd(log))
 	cmd.AddCommand(newInventoryCmd(log))
 	cmd.AddCommand(newValidatorRangesCmd(log))
 	cmd.AddCommand(newEIP7870ReferenceNodesCmd(log))
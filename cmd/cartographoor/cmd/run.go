@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -16,11 +17,20 @@ import (
 
 	"github.com/ethpandaops/cartographoor/pkg/utils"
 
+	"github.com/ethpandaops/cartographoor/pkg/api"
+	"github.com/ethpandaops/cartographoor/pkg/changelog"
 	"github.com/ethpandaops/cartographoor/pkg/clientdiscovery"
+	"github.com/ethpandaops/cartographoor/pkg/configwatch"
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
-	"github.com/ethpandaops/cartographoor/pkg/providers/github"
-	"github.com/ethpandaops/cartographoor/pkg/providers/static"
+	"github.com/ethpandaops/cartographoor/pkg/githubapi"
+	"github.com/ethpandaops/cartographoor/pkg/health"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+	"github.com/ethpandaops/cartographoor/pkg/notify"
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
 	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
+	"github.com/ethpandaops/cartographoor/pkg/trigger"
+	"github.com/ethpandaops/cartographoor/pkg/uploadguard"
 )
 
 type runConfig struct {
@@ -32,6 +42,41 @@ type runConfig struct {
 	Storage         s3.Config              `mapstructure:"storage"`
 	RunOnce         bool                   `mapstructure:"runOnce"`
 	ValidatorRanges *ValidatorRangesConfig `mapstructure:"validatorRanges"`
+	// SkipUploadOnProviderFailure refuses to upload a result when any discovery
+	// provider failed. When false, partial results are uploaded with "partial" set.
+	SkipUploadOnProviderFailure bool `mapstructure:"skipUploadOnProviderFailure"`
+	// UploadGuard blocks uploads that drop too many networks or clients compared
+	// with the published result.
+	UploadGuard uploadguard.Config `mapstructure:"uploadGuard"`
+	// ForceUpload uploads results even if the upload guard blocks them.
+	ForceUpload bool `mapstructure:"forceUpload"`
+	// Changelog publishes the changes between consecutive uploads.
+	Changelog changelog.Config `mapstructure:"changelog"`
+	// Notifications sends network lifecycle events to webhook and chat sinks.
+	Notifications notify.Config `mapstructure:"notifications"`
+	// API serves the latest discovery result over HTTP in continuous mode.
+	API api.Config `mapstructure:"api"`
+	// Triggers enables on-demand discovery runs over HTTP, served on the API
+	// listener. SIGHUP always triggers a full run.
+	Triggers trigger.Config `mapstructure:"triggers"`
+	// Metrics serves Prometheus metrics in continuous mode, or pushes them to a
+	// Pushgateway in --once mode.
+	Metrics metrics.Config `mapstructure:"metrics"`
+	// Health configures the /healthz and /readyz endpoints served on the
+	// metrics and API listeners in continuous mode.
+	Health health.Config `mapstructure:"health"`
+	// WatchConfig reloads the discovery section of the config file when it
+	// changes in continuous mode.
+	WatchConfig bool `mapstructure:"watchConfig"`
+	// HTTPCache revalidates GitHub API and raw content fetches with
+	// conditional requests.
+	HTTPCache httpcache.Config `mapstructure:"httpCache"`
+	// GitHub authenticates discovery with a personal access token or a GitHub
+	// App. Without it, discovery.github.token is used.
+	GitHub githubapi.Config `mapstructure:"github"`
+	// GitHubRateLimit configures retries of rate-limited GitHub API requests and
+	// when low-priority work is skipped to save the remaining budget.
+	GitHubRateLimit ratelimit.Config `mapstructure:"githubRateLimit"`
 }
 
 // ValidatorRangesConfig holds configuration for validator ranges generation.
@@ -53,30 +98,10 @@ func newRunCmd(log *logrus.Logger) *cobra.Command {
 		Short: "Run the Cartographoor service",
 		Long:  `Run the Cartographoor service to discover Ethereum networks and upload to S3`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			v := viper.New()
-
-			if cfg.ConfigFile != "" {
-				v.SetConfigFile(cfg.ConfigFile)
-
-				// Read and process the config file with environment variable substitution
-				if err := readConfigWithEnvSubst(v); err != nil {
-					return err
-				}
-			}
-
-			v.SetEnvPrefix("CARTOGRAPHOOR")
-			v.AutomaticEnv()
-
-			if err := v.Unmarshal(cfg); err != nil {
+			if err := loadRunConfig(log, cfg); err != nil {
 				return err
 			}
 
-			// Set log level
-			level, err := logrus.ParseLevel(cfg.Logging.Level)
-			if err == nil {
-				log.SetLevel(level)
-			}
-
 			return runService(cmd.Context(), log, cfg)
 		},
 	}
@@ -85,10 +110,52 @@ func newRunCmd(log *logrus.Logger) *cobra.Command {
 	cmd.Flags().StringVar(&cfg.ConfigFile, "config", "", "Path to config file")
 	cmd.Flags().StringVar(&cfg.Logging.Level, "logging.level", "info", "Logging level (trace, debug, info, warn, error, fatal, panic)")
 	cmd.Flags().BoolVar(&cfg.RunOnce, "once", false, "Run discovery once and exit")
+	cmd.Flags().BoolVar(&cfg.SkipUploadOnProviderFailure, "skip-upload-on-provider-failure", false, "Skip uploading results when any discovery provider failed")
+	cmd.Flags().BoolVar(&cfg.ForceUpload, "force-upload", false, "Upload results even if the upload guard blocks them")
+	cmd.Flags().BoolVar(&cfg.WatchConfig, "watch-config", true, "Reload the discovery config when the config file changes")
 
 	return cmd
 }
 
+// loadRunConfig reads the config file and environment into cfg and applies the
+// configured log level.
+func loadRunConfig(log *logrus.Logger, cfg *runConfig) error {
+	if err := readRunConfig(cfg); err != nil {
+		return err
+	}
+
+	setLogLevel(log, cfg.Logging.Level)
+
+	return nil
+}
+
+// readRunConfig reads the config file and environment into cfg.
+func readRunConfig(cfg *runConfig) error {
+	v := viper.New()
+
+	if cfg.ConfigFile != "" {
+		v.SetConfigFile(cfg.ConfigFile)
+
+		// Read and process the config file with environment variable substitution
+		if err := readConfigWithEnvSubst(v); err != nil {
+			return err
+		}
+	}
+
+	v.SetEnvPrefix("CARTOGRAPHOOR")
+	v.AutomaticEnv()
+
+	return v.Unmarshal(cfg)
+}
+
+// setLogLevel applies a log level, ignoring invalid ones.
+func setLogLevel(log *logrus.Logger, levelName string) {
+	level, err := logrus.ParseLevel(levelName)
+	if err == nil {
+		log.SetLevel(level)
+	}
+}
+
 func runService(ctx context.Context, log *logrus.Logger, cfg *runConfig) error {
 	// Set up context with cancellation
 	ctx, cancel := context.WithCancel(ctx)
@@ -98,14 +165,36 @@ func runService(ctx context.Context, log *logrus.Logger, cfg *runConfig) error {
 		Timeout: 10 * time.Second,
 	}
 
+	httpCache, err := httpcache.New(cfg.HTTPCache)
+	if err != nil {
+		return fmt.Errorf("failed to create http cache: %w", err)
+	}
+
+	rateLimits, err := ratelimit.New(cfg.GitHubRateLimit)
+	if err != nil {
+		return err
+	}
+
+	githubConfig := cfg.GitHub
+	if !githubConfig.IsSet() {
+		githubConfig.Token = cfg.Discovery.GitHub.Token
+	}
+
+	githubAuth, err := githubapi.NewTokenSource(githubConfig)
+	if err != nil {
+		return err
+	}
+
 	// Create discovery service with a GitHub-backed client discoverer
-	clientDiscoverer := clientdiscovery.New(log, cfg.Discovery.GitHub.Token)
+	clientDiscoverer := clientdiscovery.New(log, githubAuth, httpCache, rateLimits)
 
 	discoveryService, err := discovery.NewService(log, cfg.Discovery, clientDiscoverer)
 	if err != nil {
 		return err
 	}
 
+	discoveryService.ReportRateLimits(rateLimits)
+
 	// Create S3 storage provider
 	log.WithFields(logrus.Fields{
 		"endpoint":       cfg.Storage.Endpoint,
@@ -119,63 +208,191 @@ func runService(ctx context.Context, log *logrus.Logger, cfg *runConfig) error {
 		return err
 	}
 
-	// Register GitHub provider
-	githubProvider, err := github.NewProvider(log, httpClient)
-	if err != nil {
+	// Register the providers enabled in discovery.providers
+	if err := discoveryService.RegisterConfiguredProviders(discovery.ProviderDeps{
+		HTTPClient: httpClient,
+		GitHubAuth: githubAuth,
+		HTTPCache:  httpCache,
+		RateLimits: rateLimits,
+	}); err != nil {
 		return err
 	}
 
-	discoveryService.RegisterProvider(githubProvider)
+	// Seed the discovery service with the currently published result, so a
+	// failing provider can fall back to its last good networks after a restart.
+	published, err := loadPublishedResult(ctx, storageProvider)
+	if err != nil {
+		log.WithError(err).Warn("Failed to load published networks, starting without previous result")
+	} else {
+		discoveryService.Seed(*published)
+	}
+
+	// Create the notifier, comparing the first result against the published one
+	var notifier *notify.Notifier
 
-	// Register Static provider for hardcoded networks
-	staticProvider, err := static.NewProvider(log)
+	if cfg.Notifications.Enabled() {
+		notifier, err = notify.New(log, cfg.Notifications, httpClient)
+		if err != nil {
+			return err
+		}
+
+		if published != nil {
+			notifier.SetPrevious(*published)
+		}
+	}
+
+	publisher, err := newResultPublisher(log, cfg, storageProvider, notifier)
 	if err != nil {
 		return err
 	}
 
-	discoveryService.RegisterProvider(staticProvider)
-
 	// For run-once mode, we'll use a different approach
 	if cfg.RunOnce {
 		log.Info("Running in one-time discovery mode")
 
-		return runOnce(ctx, log, discoveryService, storageProvider)
+		if cfg.API.Enabled {
+			log.Warn("API server is not started in one-time discovery mode")
+		}
+
+		defer pushMetrics(log, cfg.Metrics, "cartographoor_run")
+
+		return runOnce(ctx, log, discoveryService, publisher)
 	}
 
 	// Start the service in normal mode (continuous discovery).
 	log.WithField("interval", cfg.Discovery.Interval).Info("Starting service in continuous mode")
 
+	// Track discovery and upload state for the health endpoints
+	cfg.Health.SetDefaults(discoveryService.Interval())
+	checker := health.NewChecker(cfg.Health)
+
+	// Start the metrics server
+	if cfg.Metrics.Enabled {
+		metricsServer := metrics.NewServer(log, cfg.Metrics)
+
+		for pattern, handler := range checker.Routes() {
+			metricsServer.Handle(pattern, handler)
+		}
+
+		if err := metricsServer.Start(); err != nil {
+			return err
+		}
+
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+
+			if err := metricsServer.Stop(shutdownCtx); err != nil {
+				log.WithError(err).Error("Error during metrics server shutdown")
+			}
+		}()
+	}
+
+	if cfg.Triggers.Enabled() && !cfg.API.Enabled {
+		log.Warn("HTTP discovery triggers are only served when the API is enabled")
+	}
+
+	if !cfg.Metrics.Enabled && !cfg.API.Enabled {
+		log.Warn("Health endpoints are only served when metrics or the API are enabled")
+	}
+
+	// Start the API server, serving the published result until discovery completes
+	if cfg.API.Enabled {
+		apiServer := api.NewServer(log, cfg.API)
+
+		if published != nil {
+			apiServer.SetResult(*published)
+		}
+
+		for pattern, handler := range trigger.NewHandler(log, cfg.Triggers, discoveryService).Routes() {
+			apiServer.Handle(pattern, handler)
+		}
+
+		// Health endpoints don't depend on metrics being enabled.
+		for pattern, handler := range checker.Routes() {
+			apiServer.Handle(pattern, handler)
+		}
+
+		if err := apiServer.Start(); err != nil {
+			return err
+		}
+
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+
+			if err := apiServer.Stop(shutdownCtx); err != nil {
+				log.WithError(err).Error("Error during API server shutdown")
+			}
+		}()
+
+		discoveryService.OnResult(apiServer.SetResult)
+	}
+
 	// Start discovery service
 	if err := discoveryService.Start(ctx); err != nil {
 		return err
 	}
 
+	// Reload the discovery config when the config file changes
+	if cfg.WatchConfig && cfg.ConfigFile != "" {
+		watcher, err := configwatch.New(log, cfg.ConfigFile, configwatch.DefaultDebounce, func() error {
+			return reloadDiscoveryConfig(log, cfg.ConfigFile, discoveryService)
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := watcher.Start(ctx); err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := watcher.Stop(); err != nil {
+				log.WithError(err).Error("Error during config watcher shutdown")
+			}
+		}()
+	}
+
 	// Set up discovery result handler
 	discoveryService.OnResult(func(result discovery.Result) {
 		log.WithField("networks", len(result.Networks)).Info("Discovered networks")
 
-		// Skip upload if there are no networks or discovery had errors
-		if len(result.Networks) == 0 {
-			log.Info("No networks discovered, skipping S3 upload")
+		checker.RecordDiscovery(result)
 
-			return
+		uploaded, err := publisher.publish(ctx, result)
+		if err != nil {
+			log.WithError(err).Error("Failed to publish discovery result")
 		}
 
-		// Upload to S3
-		if err := storageProvider.Upload(ctx, result); err != nil {
-			log.WithError(err).Error("Failed to upload networks to S3")
+		// A skipped upload of an empty result is neither a success nor a failure.
+		if uploaded || err != nil {
+			checker.RecordUpload(err)
 		}
 	})
 
-	// Handle graceful shutdown
+	// Handle graceful shutdown, SIGHUP triggers a discovery run
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+waitLoop:
+	for {
+		select {
+		case sig := <-sigCh:
+			if sig == syscall.SIGHUP {
+				discoveryService.Trigger("sighup")
+
+				continue
+			}
+
+			log.Info("Received shutdown signal")
+
+			break waitLoop
+		case <-ctx.Done():
+			log.Info("Context cancelled")
 
-	select {
-	case <-sigCh:
-		log.Info("Received shutdown signal")
-	case <-ctx.Done():
-		log.Info("Context cancelled")
+			break waitLoop
+		}
 	}
 
 	// Give a short grace period for cleanup
@@ -189,8 +406,26 @@ func runService(ctx context.Context, log *logrus.Logger, cfg *runConfig) error {
 	return nil
 }
 
+// reloadDiscoveryConfig rereads the config file and swaps the discovery config
+// of the running service. Other sections only apply after a restart, except
+// the log level.
+func reloadDiscoveryConfig(log *logrus.Logger, configFile string, discoveryService *discovery.Service) error {
+	next := &runConfig{ConfigFile: configFile}
+	if err := readRunConfig(next); err != nil {
+		return err
+	}
+
+	if err := discoveryService.UpdateConfig(next.Discovery); err != nil {
+		return err
+	}
+
+	setLogLevel(log, next.Logging.Level)
+
+	return nil
+}
+
 // runOnce executes a single discovery run and uploads the results.
-func runOnce(ctx context.Context, log *logrus.Logger, discoveryService *discovery.Service, storageProvider *s3.Provider) error {
+func runOnce(ctx context.Context, log *logrus.Logger, discoveryService *discovery.Service, publisher *resultPublisher) error {
 	// Create a context with timeout to ensure we don't hang indefinitely
 	runCtx, runCancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer runCancel()
@@ -204,21 +439,31 @@ func runOnce(ctx context.Context, log *logrus.Logger, discoveryService *discover
 
 	log.WithField("networks", len(result.Networks)).Info("One-time discovery complete")
 
-	// Skip upload if there are no networks
-	if len(result.Networks) == 0 {
-		log.Info("No networks discovered, skipping S3 upload")
+	uploaded, err := publisher.publish(runCtx, result)
+	if err != nil {
+		return err
+	}
 
-		return nil
+	if uploaded {
+		log.Info("Upload complete, exiting")
 	}
 
-	// Upload to S3
-	if err := storageProvider.Upload(runCtx, result); err != nil {
-		return fmt.Errorf("failed to upload networks to S3: %w", err)
+	return nil
+}
+
+// loadPublishedResult downloads and parses the currently published discovery result.
+func loadPublishedResult(ctx context.Context, storageProvider *s3.Provider) (*discovery.Result, error) {
+	data, err := storageProvider.Download(ctx, storageProvider.Key())
+	if err != nil {
+		return nil, err
 	}
 
-	log.Info("Upload complete, exiting")
+	var result discovery.Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse published networks: %w", err)
+	}
 
-	return nil
+	return &result, nil
 }
 
 // readConfigWithEnvSubst reads a config file and performs environment variable substitution.
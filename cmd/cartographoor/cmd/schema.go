@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+func newSchemaCmd(log *logrus.Logger) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of networks.json",
+		Long:  `Print the JSON Schema of networks.json, generated from the discovery types, for use in consumer code generation and validation`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := discovery.SchemaJSON()
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+
+				return err
+			}
+
+			if err := os.WriteFile(output, append(data, '\n'), 0o644); err != nil {
+				return fmt.Errorf("failed to write schema: %w", err)
+			}
+
+			log.WithFields(logrus.Fields{
+				"output":  output,
+				"version": discovery.SchemaVersion,
+			}).Info("Wrote JSON schema")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "output", "", "Write the schema to this file instead of stdout")
+
+	return cmd
+}
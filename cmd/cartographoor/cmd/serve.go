@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd(log *logrus.Logger) *cobra.Command {
+	cfg := &runConfig{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the Cartographoor service with the HTTP API enabled",
+		Long:  `Run the Cartographoor service in continuous mode and serve the latest discovery result over HTTP`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadRunConfig(log, cfg); err != nil {
+				return err
+			}
+
+			// The API only runs in continuous mode.
+			listenAddr := cfg.API.ListenAddr
+			if cmd.Flags().Changed("api.listenAddr") || listenAddr == "" {
+				listenAddr, _ = cmd.Flags().GetString("api.listenAddr")
+			}
+
+			cfg.RunOnce = false
+			cfg.API.Enabled = true
+			cfg.API.ListenAddr = listenAddr
+
+			return runService(cmd.Context(), log, cfg)
+		},
+	}
+
+	// Define flags
+	cmd.Flags().StringVar(&cfg.ConfigFile, "config", "", "Path to config file")
+	cmd.Flags().StringVar(&cfg.Logging.Level, "logging.level", "info", "Logging level (trace, debug, info, warn, error, fatal, panic)")
+	cmd.Flags().String("api.listenAddr", ":8080", "Address the HTTP API listens on")
+	cmd.Flags().BoolVar(&cfg.SkipUploadOnProviderFailure, "skip-upload-on-provider-failure", false, "Skip uploading results when any discovery provider failed")
+	cmd.Flags().BoolVar(&cfg.ForceUpload, "force-upload", false, "Upload results even if the upload guard blocks them")
+	cmd.Flags().BoolVar(&cfg.WatchConfig, "watch-config", true, "Reload the discovery config when the config file changes")
+
+	return cmd
+}
@@ -7,12 +7,18 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/ethpandaops/cartographoor/pkg/githubapi"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+	githubprovider "github.com/ethpandaops/cartographoor/pkg/providers/github"
 	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
 	"github.com/ethpandaops/cartographoor/pkg/validatorranges"
 )
@@ -24,6 +30,12 @@ type validatorRangesConfig struct {
 	ConfigFile      string
 	Storage         s3.Config               `mapstructure:"storage"`
 	ValidatorRanges *validatorranges.Config `mapstructure:"validatorRanges"`
+	Metrics         metrics.Config          `mapstructure:"metrics"`
+	HTTPCache       httpcache.Config        `mapstructure:"httpCache"`
+	GitHub          githubapi.Config        `mapstructure:"github"`
+	// Discovery is read for the ref and layout of the repositories, which
+	// locate the inventories of their networks.
+	Discovery discovery.Config `mapstructure:"discovery"`
 }
 
 func newValidatorRangesCmd(log *logrus.Logger) *cobra.Command {
@@ -116,8 +128,30 @@ func runValidatorRanges(ctx context.Context, log *logrus.Logger, cfg *validatorR
 
 	log.WithField("networks", len(discoveryResult.Networks)).Info("Downloaded networks from S3")
 
+	httpCache, err := httpcache.New(cfg.HTTPCache)
+	if err != nil {
+		return fmt.Errorf("failed to create http cache: %w", err)
+	}
+
+	githubAuth, err := githubapi.NewTokenSource(cfg.GitHub)
+	if err != nil {
+		return err
+	}
+
+	repositories, err := sourceRepositories(ctx, log, cfg.Discovery, githubAuth, httpCache)
+	if err != nil {
+		return err
+	}
+
 	// Create validator ranges service
-	service := validatorranges.NewService(storageProvider, cfg.ValidatorRanges, log)
+	service := validatorranges.NewService(
+		storageProvider,
+		cfg.ValidatorRanges,
+		repositories,
+		log,
+		githubAuth,
+		httpCache,
+	)
 
 	log.Info("Starting validator ranges generation")
 
@@ -133,7 +167,13 @@ func runValidatorRanges(ctx context.Context, log *logrus.Logger, cfg *validatorR
 	log.WithField("active_networks", len(activeNetworks)).Info("Processing active networks")
 
 	// Generate validator ranges for all networks
-	if err := service.GenerateValidatorRanges(ctx, activeNetworks); err != nil {
+	start := time.Now()
+	err = service.GenerateValidatorRanges(ctx, activeNetworks)
+
+	metrics.ObserveGeneration(metrics.GeneratorValidatorRanges, start, err)
+	pushMetrics(log, cfg.Metrics, "cartographoor_validator_ranges")
+
+	if err != nil {
 		return fmt.Errorf("validator ranges generation failed: %w", err)
 	}
 
@@ -141,3 +181,30 @@ func runValidatorRanges(ctx context.Context, log *logrus.Logger, cfg *validatorR
 
 	return nil
 }
+
+// sourceRepositories returns the configured GitHub repositories and those of
+// the configured sources, whose ref and layout locate the inventories.
+func sourceRepositories(
+	ctx context.Context,
+	log *logrus.Logger,
+	config discovery.Config,
+	auth oauth2.TokenSource,
+	cache *httpcache.Cache,
+) ([]discovery.GitHubRepositoryConfig, error) {
+	if len(config.GitHub.Sources) == 0 {
+		return config.GitHub.Repositories, nil
+	}
+
+	lister, err := githubprovider.NewProvider(log, discovery.ProviderDeps{GitHubAuth: auth, HTTPCache: cache}, githubprovider.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github provider: %w", err)
+	}
+
+	listed, err := discovery.ListSourceRepositories(ctx, config, lister)
+	if err != nil {
+		// Repositories of the sources that failed fall back to the default layout.
+		log.WithError(err).Warn("Failed to list source repositories")
+	}
+
+	return discovery.ExpandSources(config, listed), nil
+}
@@ -0,0 +1,273 @@
+// Package api serves the latest discovery result over a read-only REST API.
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// Config represents the configuration for the API server.
+type Config struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	ListenAddr string `mapstructure:"listenAddr"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	if c.ListenAddr == "" {
+		c.ListenAddr = ":8080"
+	}
+}
+
+// Server serves the latest discovery result.
+type Server struct {
+	log    logrus.FieldLogger
+	config Config
+	mux    *http.ServeMux
+	server *http.Server
+	result *discovery.Result
+	mutex  sync.RWMutex
+}
+
+// NewServer creates a new API server.
+func NewServer(log logrus.FieldLogger, config Config) *Server {
+	config.SetDefaults()
+
+	s := &Server{
+		log:    log.WithField("module", "api"),
+		config: config,
+		mux:    http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /networks", s.handleNetworks)
+	s.mux.HandleFunc("GET /networks/{name}", s.handleNetwork)
+	s.mux.HandleFunc("GET /clients", s.handleClients)
+	s.mux.HandleFunc("GET /metadata", s.handleMetadata)
+
+	s.server = &http.Server{
+		Addr:              config.ListenAddr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// Handler returns the HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Handle registers an additional handler, e.g. for discovery triggers. It must
+// be called before Start.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// SetResult replaces the served discovery result.
+func (s *Server) SetResult(result discovery.Result) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.result = &result
+}
+
+// Start starts listening in the background.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.config.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.ListenAddr, err)
+	}
+
+	s.log.WithField("addr", listener.Addr().String()).Info("Starting API server")
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.WithError(err).Error("API server failed")
+		}
+	}()
+
+	return nil
+}
+
+// Stop gracefully shuts down the server.
+func (s *Server) Stop(ctx context.Context) error {
+	s.log.Info("Stopping API server")
+
+	return s.server.Shutdown(ctx)
+}
+
+// handleNetworks serves all networks, optionally filtered by status,
+// repository and chainId.
+func (s *Server) handleNetworks(w http.ResponseWriter, r *http.Request) {
+	result, ok := s.currentResult(w)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	var chainID uint64
+
+	if value := query.Get("chainId"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid chainId %q", value))
+
+			return
+		}
+
+		chainID = parsed
+	}
+
+	networks := make(map[string]discovery.Network, len(result.Networks))
+
+	for name, network := range result.Networks {
+		if status := query.Get("status"); status != "" && network.Status != status {
+			continue
+		}
+
+		if repository := query.Get("repository"); repository != "" && network.Repository != repository {
+			continue
+		}
+
+		if chainID != 0 && network.ChainID != chainID {
+			continue
+		}
+
+		networks[name] = network
+	}
+
+	s.write(w, r, result, networks)
+}
+
+// handleNetwork serves a single network by name.
+func (s *Server) handleNetwork(w http.ResponseWriter, r *http.Request) {
+	result, ok := s.currentResult(w)
+	if !ok {
+		return
+	}
+
+	name := r.PathValue("name")
+
+	network, exists := result.Networks[name]
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("network %q not found", name))
+
+		return
+	}
+
+	s.write(w, r, result, network)
+}
+
+// handleClients serves the discovered client information.
+func (s *Server) handleClients(w http.ResponseWriter, r *http.Request) {
+	if result, ok := s.currentResult(w); ok {
+		s.write(w, r, result, result.Clients)
+	}
+}
+
+// handleMetadata serves the repository metadata.
+func (s *Server) handleMetadata(w http.ResponseWriter, r *http.Request) {
+	if result, ok := s.currentResult(w); ok {
+		s.write(w, r, result, result.NetworkMetadata)
+	}
+}
+
+// currentResult returns the served result, or writes a 503 if discovery has
+// not produced one yet.
+func (s *Server) currentResult(w http.ResponseWriter) (*discovery.Result, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.result == nil {
+		writeError(w, http.StatusServiceUnavailable, "no discovery result available yet")
+
+		return nil, false
+	}
+
+	return s.result, true
+}
+
+// write encodes the response body with an ETag of its content and the result's
+// last update as Last-Modified, answering conditional requests with 304.
+func (s *Server) write(w http.ResponseWriter, r *http.Request, result *discovery.Result, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		s.log.WithError(err).Error("Failed to encode API response")
+		writeError(w, http.StatusInternalServerError, "failed to encode response")
+
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := result.LastUpdate.UTC().Truncate(time.Second)
+
+	w.Header().Set("ETag", etag)
+
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(data); err != nil {
+		s.log.WithError(err).Debug("Failed to write API response")
+	}
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == etag || tag == "*" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	since := r.Header.Get("If-Modified-Since")
+	if since == "" || lastModified.IsZero() {
+		return false
+	}
+
+	t, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(t)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
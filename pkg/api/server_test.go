@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := NewServer(logrus.New(), Config{})
+	server.SetResult(discovery.Result{
+		LastUpdate: time.Date(2026, 5, 4, 15, 30, 0, 0, time.UTC),
+		Networks: map[string]discovery.Network{
+			"mainnet":         {Name: "mainnet", Status: "active", ChainID: 1},
+			"fusaka-devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "inactive", ChainID: 7088110746},
+			"fusaka-devnet-2": {Name: "devnet-2", Repository: "ethpandaops/fusaka-devnets", Status: "active", ChainID: 7088110747},
+		},
+		Clients: map[string]discovery.ClientInfo{
+			"lighthouse": {Name: "lighthouse", Type: "consensus"},
+		},
+		NetworkMetadata: map[string]discovery.RepositoryMetadata{
+			"fusaka": {DisplayName: "Fusaka Devnets"},
+		},
+	})
+
+	return server
+}
+
+func get(t *testing.T, handler http.Handler, target string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestNetworks_Filters(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{target: "/networks", want: []string{"mainnet", "fusaka-devnet-1", "fusaka-devnet-2"}},
+		{target: "/networks?status=active", want: []string{"mainnet", "fusaka-devnet-2"}},
+		{target: "/networks?repository=ethpandaops/fusaka-devnets", want: []string{"fusaka-devnet-1", "fusaka-devnet-2"}},
+		{target: "/networks?repository=ethpandaops/fusaka-devnets&status=active", want: []string{"fusaka-devnet-2"}},
+		{target: "/networks?chainId=1", want: []string{"mainnet"}},
+		{target: "/networks?chainId=5", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := get(t, handler, tt.target, nil)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var networks map[string]discovery.Network
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &networks))
+
+			names := make([]string, 0, len(networks))
+			for name := range networks {
+				names = append(names, name)
+			}
+
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+
+	rec := get(t, handler, "/networks?chainId=abc", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestNetwork(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	rec := get(t, handler, "/networks/fusaka-devnet-2", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var network discovery.Network
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &network))
+	assert.Equal(t, uint64(7088110747), network.ChainID)
+
+	rec = get(t, handler, "/networks/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestClientsAndMetadata(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	rec := get(t, handler, "/clients", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"lighthouse"`)
+
+	rec = get(t, handler, "/metadata", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"Fusaka Devnets"`)
+}
+
+func TestConditionalRequests(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	rec := get(t, handler, "/networks", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "Mon, 04 May 2026 15:30:00 GMT", rec.Header().Get("Last-Modified"))
+
+	rec = get(t, handler, "/networks", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// A filtered response has its own ETag.
+	rec = get(t, handler, "/networks?status=active", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = get(t, handler, "/networks", map[string]string{"If-Modified-Since": "Mon, 04 May 2026 15:30:00 GMT"})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec = get(t, handler, "/networks", map[string]string{"If-Modified-Since": "Mon, 04 May 2026 15:00:00 GMT"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestNoResultYet(t *testing.T) {
+	handler := NewServer(logrus.New(), Config{}).Handler()
+
+	rec := get(t, handler, "/networks", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -0,0 +1,184 @@
+// Package chainclock converts between the slots, epochs and timestamps of a
+// beacon chain whose slot duration or slots per epoch change at forks. The
+// timing of the chain is a list of segments, each starting at an epoch and
+// lasting until the next one.
+package chainclock
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Default timing of the mainnet preset.
+const (
+	DefaultSlotsPerEpoch = 32
+	DefaultSlotDuration  = 12 * time.Second
+)
+
+// Segment is the timing of the chain from StartEpoch until the next segment.
+// A zero SlotsPerEpoch or SlotDuration keeps the value of the previous
+// segment, or the default for the first one.
+type Segment struct {
+	StartEpoch    uint64
+	SlotsPerEpoch uint64
+	SlotDuration  time.Duration
+}
+
+// segment is a Segment with where it starts in slots and time.
+type segment struct {
+	Segment
+	startSlot uint64
+	// startMS is the time since genesis, in milliseconds.
+	startMS uint64
+	// slotMS is the slot duration, in milliseconds.
+	slotMS uint64
+}
+
+// Clock converts between the slots, epochs and timestamps of a chain.
+// Timestamps are Unix times in seconds.
+type Clock struct {
+	genesisTime uint64
+	segments    []segment
+}
+
+// New creates the clock of a chain that started at genesisTime with the given
+// timing. The segments don't need to be sorted. If none starts at epoch 0, the
+// chain starts with the default timing. Segments starting at the same epoch
+// are merged, the values of later ones taking precedence.
+func New(genesisTime uint64, segments ...Segment) (*Clock, error) {
+	sorted := slices.Clone(segments)
+	slices.SortStableFunc(sorted, func(a, b Segment) int {
+		return cmp.Compare(a.StartEpoch, b.StartEpoch)
+	})
+
+	merged := make([]Segment, 0, len(sorted)+1)
+
+	for _, s := range sorted {
+		if s.SlotDuration < 0 || s.SlotDuration%time.Millisecond != 0 {
+			return nil, fmt.Errorf("slot duration of the segment at epoch %d must be a positive number of milliseconds, got %s", s.StartEpoch, s.SlotDuration)
+		}
+
+		if n := len(merged); n > 0 && merged[n-1].StartEpoch == s.StartEpoch {
+			merged[n-1] = mergeSegments(merged[n-1], s)
+
+			continue
+		}
+
+		merged = append(merged, s)
+	}
+
+	if len(merged) == 0 || merged[0].StartEpoch > 0 {
+		merged = slices.Insert(merged, 0, Segment{})
+	}
+
+	clock := &Clock{
+		genesisTime: genesisTime,
+		segments:    make([]segment, 0, len(merged)),
+	}
+
+	previous := segment{Segment: Segment{SlotsPerEpoch: DefaultSlotsPerEpoch, SlotDuration: DefaultSlotDuration}}
+
+	for i, s := range merged {
+		current := segment{Segment: mergeSegments(previous.Segment, s)}
+		current.StartEpoch = s.StartEpoch
+		current.slotMS = uint64(current.SlotDuration.Milliseconds())
+
+		if i > 0 {
+			epochs := current.StartEpoch - previous.StartEpoch
+			current.startSlot = previous.startSlot + epochs*previous.SlotsPerEpoch
+			current.startMS = previous.startMS + epochs*previous.SlotsPerEpoch*previous.slotMS
+		}
+
+		clock.segments = append(clock.segments, current)
+		previous = current
+	}
+
+	return clock, nil
+}
+
+// mergeSegments returns base with the values set in override.
+func mergeSegments(base, override Segment) Segment {
+	if override.SlotsPerEpoch != 0 {
+		base.SlotsPerEpoch = override.SlotsPerEpoch
+	}
+
+	if override.SlotDuration != 0 {
+		base.SlotDuration = override.SlotDuration
+	}
+
+	return base
+}
+
+// GenesisTime returns the genesis time of the chain.
+func (c *Clock) GenesisTime() uint64 {
+	return c.genesisTime
+}
+
+// Segments returns the timing of the chain, sorted by epoch, with the values
+// inherited from earlier segments filled in.
+func (c *Clock) Segments() []Segment {
+	segments := make([]Segment, len(c.segments))
+	for i, s := range c.segments {
+		segments[i] = s.Segment
+	}
+
+	return segments
+}
+
+// EpochStartSlot returns the first slot of epoch.
+func (c *Clock) EpochStartSlot(epoch uint64) uint64 {
+	s := c.segmentOf(func(s segment) bool { return s.StartEpoch <= epoch })
+
+	return s.startSlot + (epoch-s.StartEpoch)*s.SlotsPerEpoch
+}
+
+// SlotEpoch returns the epoch of slot.
+func (c *Clock) SlotEpoch(slot uint64) uint64 {
+	s := c.segmentOf(func(s segment) bool { return s.startSlot <= slot })
+
+	return s.StartEpoch + (slot-s.startSlot)/s.SlotsPerEpoch
+}
+
+// EpochTimestamp returns the time epoch starts.
+func (c *Clock) EpochTimestamp(epoch uint64) uint64 {
+	return c.SlotTimestamp(c.EpochStartSlot(epoch))
+}
+
+// SlotTimestamp returns the time slot starts.
+func (c *Clock) SlotTimestamp(slot uint64) uint64 {
+	s := c.segmentOf(func(s segment) bool { return s.startSlot <= slot })
+
+	return c.genesisTime + (s.startMS+(slot-s.startSlot)*s.slotMS)/1000
+}
+
+// SlotAt returns the slot in progress at timestamp, 0 before genesis.
+func (c *Clock) SlotAt(timestamp uint64) uint64 {
+	if timestamp <= c.genesisTime {
+		return 0
+	}
+
+	elapsedMS := (timestamp - c.genesisTime) * 1000
+	s := c.segmentOf(func(s segment) bool { return s.startMS <= elapsedMS })
+
+	return s.startSlot + (elapsedMS-s.startMS)/s.slotMS
+}
+
+// EpochAt returns the epoch in progress at timestamp, 0 before genesis.
+func (c *Clock) EpochAt(timestamp uint64) uint64 {
+	return c.SlotEpoch(c.SlotAt(timestamp))
+}
+
+// segmentOf returns the last segment that starts at or before a point, given
+// by started, which holds for a prefix of the segments.
+func (c *Clock) segmentOf(started func(segment) bool) segment {
+	i := sort.Search(len(c.segments), func(i int) bool {
+		return !started(c.segments[i])
+	})
+
+	// The first segment starts at genesis, so only a point before genesis
+	// has no segment.
+	return c.segments[max(i-1, 0)]
+}
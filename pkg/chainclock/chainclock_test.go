@@ -0,0 +1,99 @@
+package chainclock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock_ConstantTiming(t *testing.T) {
+	clock, err := New(1000)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Segment{{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second}}, clock.Segments())
+
+	assert.Equal(t, uint64(1000), clock.EpochTimestamp(0))
+	assert.Equal(t, uint64(1000+412672*32*12), clock.EpochTimestamp(412672))
+	assert.Equal(t, uint64(64), clock.EpochStartSlot(2))
+	assert.Equal(t, uint64(2), clock.SlotEpoch(95))
+	assert.Equal(t, uint64(1012), clock.SlotTimestamp(1))
+
+	assert.Equal(t, uint64(0), clock.SlotAt(500), "before genesis")
+	assert.Equal(t, uint64(0), clock.SlotAt(1011))
+	assert.Equal(t, uint64(1), clock.SlotAt(1012))
+	assert.Equal(t, uint64(1), clock.EpochAt(1000+32*12))
+}
+
+func TestClock_VariableTiming(t *testing.T) {
+	// 12 second slots until epoch 10, then 6 second slots, then 16 slots per
+	// epoch from epoch 20.
+	clock, err := New(1000,
+		Segment{StartEpoch: 20, SlotsPerEpoch: 16},
+		Segment{StartEpoch: 10, SlotDuration: 6 * time.Second},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Segment{
+		{StartEpoch: 0, SlotsPerEpoch: 32, SlotDuration: 12 * time.Second},
+		{StartEpoch: 10, SlotsPerEpoch: 32, SlotDuration: 6 * time.Second},
+		{StartEpoch: 20, SlotsPerEpoch: 16, SlotDuration: 6 * time.Second},
+	}, clock.Segments())
+
+	fork := uint64(1000 + 10*32*12)
+	second := fork + 10*32*6
+
+	assert.Equal(t, fork, clock.EpochTimestamp(10))
+	assert.Equal(t, fork+32*6, clock.EpochTimestamp(11))
+	assert.Equal(t, second, clock.EpochTimestamp(20))
+	assert.Equal(t, second+5*16*6, clock.EpochTimestamp(25))
+
+	assert.Equal(t, uint64(640), clock.EpochStartSlot(20))
+	assert.Equal(t, uint64(640+16), clock.EpochStartSlot(21))
+	assert.Equal(t, uint64(19), clock.SlotEpoch(639))
+	assert.Equal(t, uint64(21), clock.SlotEpoch(656))
+
+	assert.Equal(t, uint64(320), clock.SlotAt(fork))
+	assert.Equal(t, uint64(321), clock.SlotAt(fork+6))
+	assert.Equal(t, uint64(319), clock.SlotAt(fork-1))
+	assert.Equal(t, uint64(20), clock.EpochAt(second))
+	assert.Equal(t, uint64(21), clock.EpochAt(second+16*6))
+
+	// Round trips across segments.
+	for _, epoch := range []uint64{0, 9, 10, 19, 20, 1000} {
+		assert.Equal(t, epoch, clock.EpochAt(clock.EpochTimestamp(epoch)))
+	}
+}
+
+func TestClock_SubSecondSlots(t *testing.T) {
+	clock, err := New(1000, Segment{SlotsPerEpoch: 4, SlotDuration: 1500 * time.Millisecond})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1006), clock.EpochTimestamp(1))
+	assert.Equal(t, uint64(1001), clock.SlotTimestamp(1), "rounded down to the second")
+	assert.Equal(t, uint64(2), clock.SlotAt(1003))
+}
+
+func TestNew_MergesSegments(t *testing.T) {
+	clock, err := New(0,
+		Segment{SlotsPerEpoch: 8},
+		Segment{StartEpoch: 5, SlotDuration: 4 * time.Second},
+		Segment{StartEpoch: 5, SlotsPerEpoch: 16},
+		Segment{SlotDuration: 2 * time.Second},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Segment{
+		{StartEpoch: 0, SlotsPerEpoch: 8, SlotDuration: 2 * time.Second},
+		{StartEpoch: 5, SlotsPerEpoch: 16, SlotDuration: 4 * time.Second},
+	}, clock.Segments())
+}
+
+func TestNew_InvalidSlotDuration(t *testing.T) {
+	_, err := New(0, Segment{SlotDuration: -time.Second})
+	require.Error(t, err)
+
+	_, err = New(0, Segment{StartEpoch: 3, SlotDuration: time.Microsecond})
+	require.Error(t, err)
+}
@@ -0,0 +1,259 @@
+// Package changelog computes the changes between two discovery results, such
+// as networks being added or archived, forks being scheduled and images being
+// bumped, and publishes them next to networks.json.
+package changelog
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// Change types reported in a Changelog.
+const (
+	NetworkAdded        = "network_added"
+	NetworkRemoved      = "network_removed"
+	StatusChanged       = "status_changed"
+	ForkScheduled       = "fork_scheduled"
+	ForkRescheduled     = "fork_rescheduled"
+	ForkUnscheduled     = "fork_unscheduled"
+	BlobScheduleChanged = "blob_schedule_changed"
+	ImageUpdated        = "image_updated"
+	ServiceURLAdded     = "service_url_added"
+	ServiceURLRemoved   = "service_url_removed"
+)
+
+// Change is a single difference between two discovery results.
+type Change struct {
+	Type       string `json:"type"`
+	Network    string `json:"network"`
+	Repository string `json:"repository,omitempty"`
+	// Field names the fork, image or service the change applies to.
+	Field string `json:"field,omitempty"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// Changelog lists the changes between two discovery results.
+type Changelog struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Changes []Change  `json:"changes"`
+}
+
+// Empty returns true if the changelog contains no changes.
+func (c *Changelog) Empty() bool {
+	return len(c.Changes) == 0
+}
+
+// Diff returns the changes from the previous to the current discovery result.
+// Changes are sorted by network, type and field so the output is stable.
+func Diff(previous, current discovery.Result) *Changelog {
+	changes := make([]Change, 0)
+
+	for name, curr := range current.Networks {
+		prev, ok := previous.Networks[name]
+		if !ok {
+			changes = append(changes, Change{
+				Type:       NetworkAdded,
+				Network:    name,
+				Repository: curr.Repository,
+				To:         curr.Status,
+			})
+
+			continue
+		}
+
+		changes = append(changes, diffNetwork(name, prev, curr)...)
+	}
+
+	for name, prev := range previous.Networks {
+		if _, ok := current.Networks[name]; !ok {
+			changes = append(changes, Change{
+				Type:       NetworkRemoved,
+				Network:    name,
+				Repository: prev.Repository,
+				From:       prev.Status,
+			})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return cmp.Or(
+			cmp.Compare(a.Network, b.Network),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Field, b.Field),
+		)
+	})
+
+	return &Changelog{
+		From:    previous.LastUpdate,
+		To:      current.LastUpdate,
+		Changes: changes,
+	}
+}
+
+// diffNetwork returns the changes between two versions of the same network.
+func diffNetwork(name string, prev, curr discovery.Network) []Change {
+	changes := make([]Change, 0)
+
+	newChange := func(changeType, field, from, to string) Change {
+		return Change{
+			Type:       changeType,
+			Network:    name,
+			Repository: curr.Repository,
+			Field:      field,
+			From:       from,
+			To:         to,
+		}
+	}
+
+	if prev.Status != curr.Status {
+		changes = append(changes, newChange(StatusChanged, "", prev.Status, curr.Status))
+	}
+
+	// Forks are compared by activation point, ignoring derived timestamps.
+	for _, fork := range diffMaps(forkActivations(prev.Forks), forkActivations(curr.Forks)) {
+		switch {
+		case fork.from == "":
+			changes = append(changes, newChange(ForkScheduled, fork.key, "", fork.to))
+		case fork.to == "":
+			changes = append(changes, newChange(ForkUnscheduled, fork.key, fork.from, ""))
+		default:
+			changes = append(changes, newChange(ForkRescheduled, fork.key, fork.from, fork.to))
+		}
+	}
+
+	if prevSchedule, currSchedule := formatBlobSchedule(prev.BlobSchedule), formatBlobSchedule(curr.BlobSchedule); prevSchedule != currSchedule {
+		changes = append(changes, newChange(BlobScheduleChanged, "", prevSchedule, currSchedule))
+	}
+
+	for _, image := range diffMaps(imageVersions(prev.Images), imageVersions(curr.Images)) {
+		changes = append(changes, newChange(ImageUpdated, image.key, image.from, image.to))
+	}
+
+	for _, service := range diffMaps(serviceURLs(prev.ServiceURLs), serviceURLs(curr.ServiceURLs)) {
+		switch {
+		case service.from == "":
+			changes = append(changes, newChange(ServiceURLAdded, service.key, "", service.to))
+		case service.to == "":
+			changes = append(changes, newChange(ServiceURLRemoved, service.key, service.from, ""))
+		default:
+			// A service moving to another URL is reported as removed and added.
+			changes = append(changes,
+				newChange(ServiceURLRemoved, service.key, service.from, ""),
+				newChange(ServiceURLAdded, service.key, "", service.to),
+			)
+		}
+	}
+
+	return changes
+}
+
+// valueChange is a key whose value differs between two maps. An empty from or
+// to means the key was missing on that side.
+type valueChange struct {
+	key  string
+	from string
+	to   string
+}
+
+// diffMaps returns the keys whose values differ between prev and curr.
+func diffMaps(prev, curr map[string]string) []valueChange {
+	changes := make([]valueChange, 0)
+
+	for key, to := range curr {
+		if from := prev[key]; from != to {
+			changes = append(changes, valueChange{key: key, from: from, to: to})
+		}
+	}
+
+	for key, from := range prev {
+		if _, ok := curr[key]; !ok {
+			changes = append(changes, valueChange{key: key, from: from})
+		}
+	}
+
+	return changes
+}
+
+// forkActivations returns the activation point of every fork, keyed by fork name.
+// Execution forks are prefixed with "execution." to keep them apart from
+// consensus forks of the same name.
+func forkActivations(forks *discovery.ForksConfig) map[string]string {
+	activations := make(map[string]string)
+
+	if forks == nil {
+		return activations
+	}
+
+	for name, fork := range forks.Consensus {
+		activations[name] = fmt.Sprintf("epoch %d", fork.Epoch)
+	}
+
+	for name, fork := range forks.Execution {
+		activation := fmt.Sprintf("block %d", fork.Block)
+		if fork.Block == 0 && fork.Timestamp > 0 {
+			activation = fmt.Sprintf("timestamp %d", fork.Timestamp)
+		}
+
+		activations["execution."+name] = activation
+	}
+
+	return activations
+}
+
+// formatBlobSchedule formats a blob schedule as a comparable string.
+func formatBlobSchedule(schedule []discovery.BlobSchedule) string {
+	entries := make([]string, 0, len(schedule))
+
+	for _, entry := range schedule {
+		entries = append(entries, fmt.Sprintf("epoch %d: %d blobs", entry.Epoch, entry.MaxBlobsPerBlock))
+	}
+
+	return strings.Join(entries, ", ")
+}
+
+// imageVersions returns the version of every client and tool image, keyed by name.
+func imageVersions(images *discovery.Images) map[string]string {
+	versions := make(map[string]string)
+
+	if images == nil {
+		return versions
+	}
+
+	for _, client := range images.Clients {
+		versions[client.Name] = client.Version
+	}
+
+	for _, tool := range images.Tools {
+		versions[tool.Name] = tool.Version
+	}
+
+	return versions
+}
+
+// serviceURLs returns the non-empty service URLs keyed by their JSON name.
+func serviceURLs(services *discovery.ServiceURLs) map[string]string {
+	urls := make(map[string]string)
+
+	if services == nil {
+		return urls
+	}
+
+	data, err := json.Marshal(services)
+	if err != nil {
+		return urls
+	}
+
+	if err := json.Unmarshal(data, &urls); err != nil {
+		return make(map[string]string)
+	}
+
+	return urls
+}
@@ -0,0 +1,134 @@
+package changelog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+func TestDiff_NetworksAddedAndRemoved(t *testing.T) {
+	previous := discovery.Result{
+		LastUpdate: time.Unix(100, 0),
+		Networks: map[string]discovery.Network{
+			"devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "active"},
+		},
+	}
+	current := discovery.Result{
+		LastUpdate: time.Unix(200, 0),
+		Networks: map[string]discovery.Network{
+			"devnet-2": {Name: "devnet-2", Repository: "ethpandaops/fusaka-devnets", Status: "active"},
+		},
+	}
+
+	changelog := Diff(previous, current)
+
+	assert.Equal(t, time.Unix(100, 0), changelog.From)
+	assert.Equal(t, time.Unix(200, 0), changelog.To)
+	assert.Equal(t, []Change{
+		{Type: NetworkRemoved, Network: "devnet-1", Repository: "ethpandaops/fusaka-devnets", From: "active"},
+		{Type: NetworkAdded, Network: "devnet-2", Repository: "ethpandaops/fusaka-devnets", To: "active"},
+	}, changelog.Changes)
+}
+
+func TestDiff_NetworkChanges(t *testing.T) {
+	previous := discovery.Network{
+		Name:   "devnet-1",
+		Status: "active",
+		Forks: &discovery.ForksConfig{
+			Consensus: map[string]discovery.ConsensusForkConfig{
+				"electra": {Epoch: 10},
+				"fulu":    {Epoch: 100},
+			},
+		},
+		BlobSchedule: []discovery.BlobSchedule{{Epoch: 10, MaxBlobsPerBlock: 9}},
+		Images: &discovery.Images{
+			Clients: []discovery.ClientImage{{Name: "lighthouse", Version: "v1.0.0"}},
+		},
+		ServiceURLs: &discovery.ServiceURLs{
+			Faucet: "https://faucet.devnet-1.example.com",
+			Dora:   "https://dora.devnet-1.example.com",
+		},
+	}
+	current := discovery.Network{
+		Name:   "devnet-1",
+		Status: "inactive",
+		Forks: &discovery.ForksConfig{
+			Consensus: map[string]discovery.ConsensusForkConfig{
+				"electra": {Epoch: 10, Timestamp: 12345},
+				"fulu":    {Epoch: 200},
+				"gloas":   {Epoch: 300},
+			},
+		},
+		BlobSchedule: []discovery.BlobSchedule{{Epoch: 10, MaxBlobsPerBlock: 12}},
+		Images: &discovery.Images{
+			Clients: []discovery.ClientImage{{Name: "lighthouse", Version: "v1.1.0"}},
+		},
+		ServiceURLs: &discovery.ServiceURLs{
+			Faucet:    "https://faucet.devnet-1.example.com",
+			Assertoor: "https://assertoor.devnet-1.example.com",
+		},
+	}
+
+	changelog := Diff(
+		discovery.Result{Networks: map[string]discovery.Network{"devnet-1": previous}},
+		discovery.Result{Networks: map[string]discovery.Network{"devnet-1": current}},
+	)
+
+	assert.Equal(t, []Change{
+		{Type: BlobScheduleChanged, Network: "devnet-1", From: "epoch 10: 9 blobs", To: "epoch 10: 12 blobs"},
+		{Type: ForkRescheduled, Network: "devnet-1", Field: "fulu", From: "epoch 100", To: "epoch 200"},
+		{Type: ForkScheduled, Network: "devnet-1", Field: "gloas", To: "epoch 300"},
+		{Type: ImageUpdated, Network: "devnet-1", Field: "lighthouse", From: "v1.0.0", To: "v1.1.0"},
+		{Type: ServiceURLAdded, Network: "devnet-1", Field: "assertoor", To: "https://assertoor.devnet-1.example.com"},
+		{Type: ServiceURLRemoved, Network: "devnet-1", Field: "dora", From: "https://dora.devnet-1.example.com"},
+		{Type: StatusChanged, Network: "devnet-1", From: "active", To: "inactive"},
+	}, changelog.Changes)
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	result := discovery.Result{
+		Networks: map[string]discovery.Network{
+			"devnet-1": {
+				Name:   "devnet-1",
+				Status: "active",
+				Forks: &discovery.ForksConfig{
+					Execution: map[string]discovery.ExecutionForkConfig{
+						"prague": {Timestamp: 1700000000},
+					},
+				},
+			},
+		},
+	}
+
+	changelog := Diff(result, result)
+
+	require.NotNil(t, changelog.Changes)
+	assert.True(t, changelog.Empty())
+}
+
+func TestDiff_ExecutionForks(t *testing.T) {
+	previous := discovery.Network{
+		Name: "devnet-1",
+		Forks: &discovery.ForksConfig{
+			Execution: map[string]discovery.ExecutionForkConfig{
+				"prague": {Timestamp: 1700000000},
+				"osaka":  {Block: 100},
+			},
+		},
+	}
+	current := discovery.Network{Name: "devnet-1"}
+
+	changelog := Diff(
+		discovery.Result{Networks: map[string]discovery.Network{"devnet-1": previous}},
+		discovery.Result{Networks: map[string]discovery.Network{"devnet-1": current}},
+	)
+
+	assert.Equal(t, []Change{
+		{Type: ForkUnscheduled, Network: "devnet-1", Field: "execution.osaka", From: "block 100"},
+		{Type: ForkUnscheduled, Network: "devnet-1", Field: "execution.prague", From: "timestamp 1700000000"},
+	}, changelog.Changes)
+}
@@ -0,0 +1,126 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
+)
+
+// Config represents the configuration for publishing changelogs.
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// Key is the S3 key of the latest changelog.
+	Key string `mapstructure:"key"`
+
+	// HistoryKey is the S3 key of the append-only changelog history.
+	HistoryKey string `mapstructure:"historyKey"`
+
+	// MaxHistoryEntries caps the number of changelogs kept in the history,
+	// dropping the oldest first. Zero keeps all entries.
+	MaxHistoryEntries int `mapstructure:"maxHistoryEntries"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	if c.Key == "" {
+		c.Key = "changes.json"
+	}
+
+	if c.HistoryKey == "" {
+		c.HistoryKey = "changes-history.json"
+	}
+}
+
+// History is the append-only list of changelogs, oldest first.
+type History struct {
+	Entries []Changelog `json:"entries"`
+}
+
+// Publisher uploads changelogs to S3.
+type Publisher struct {
+	log     logrus.FieldLogger
+	config  Config
+	storage *s3.Provider
+}
+
+// NewPublisher creates a new changelog publisher.
+func NewPublisher(log logrus.FieldLogger, config Config, storage *s3.Provider) *Publisher {
+	config.SetDefaults()
+
+	return &Publisher{
+		log:     log.WithField("module", "changelog"),
+		config:  config,
+		storage: storage,
+	}
+}
+
+// Publish uploads the changelog as the latest changes and, if it is not empty,
+// appends it to the history.
+func (p *Publisher) Publish(ctx context.Context, changelog *Changelog) error {
+	data, err := json.MarshalIndent(changelog, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal changelog: %w", err)
+	}
+
+	if err := p.storage.UploadRaw(ctx, p.config.Key, data, "application/json"); err != nil {
+		return fmt.Errorf("failed to upload changelog: %w", err)
+	}
+
+	p.log.WithField("changes", len(changelog.Changes)).Info("Published changelog")
+
+	if changelog.Empty() {
+		return nil
+	}
+
+	history, err := p.loadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	history.Entries = append(history.Entries, *changelog)
+
+	if p.config.MaxHistoryEntries > 0 && len(history.Entries) > p.config.MaxHistoryEntries {
+		history.Entries = history.Entries[len(history.Entries)-p.config.MaxHistoryEntries:]
+	}
+
+	data, err = json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal changelog history: %w", err)
+	}
+
+	if err := p.storage.UploadRaw(ctx, p.config.HistoryKey, data, "application/json"); err != nil {
+		return fmt.Errorf("failed to upload changelog history: %w", err)
+	}
+
+	p.log.WithField("entries", len(history.Entries)).Debug("Appended changelog to history")
+
+	return nil
+}
+
+// loadHistory downloads the existing history. A missing history starts empty,
+// while any other failure is an error so the history is never overwritten.
+func (p *Publisher) loadHistory(ctx context.Context) (*History, error) {
+	history := &History{Entries: []Changelog{}}
+
+	data, err := p.storage.Download(ctx, p.config.HistoryKey)
+	if s3.IsNotFound(err) {
+		p.log.Info("No changelog history found, starting a new one")
+
+		return history, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to download changelog history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse changelog history: %w", err)
+	}
+
+	return history, nil
+}
@@ -260,6 +260,10 @@ func (m *MemoryProvider) refresh(ctx context.Context) error {
 		return fmt.Errorf("decode response: %w", err)
 	}
 
+	if err := checkSchemaVersion(result.SchemaVersion); err != nil {
+		return fmt.Errorf("incompatible response: %w", err)
+	}
+
 	// Update state
 	m.mu.Lock()
 	m.networks = result.Networks
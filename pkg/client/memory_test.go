@@ -2,6 +2,9 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -111,3 +114,42 @@ func TestMemoryProviderFiltering(t *testing.T) {
 	assert.Len(t, execution, 1)
 	assert.Contains(t, execution, "geth")
 }
+
+func TestMemoryProvider_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "current", version: discovery.SchemaVersion},
+		{name: "newer minor", version: "1.99.0"},
+		{name: "unversioned", version: ""},
+		{name: "incompatible major", version: "2.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_ = json.NewEncoder(w).Encode(discovery.Result{
+					SchemaVersion: tt.version,
+					Networks:      map[string]discovery.Network{"mainnet": {Name: "mainnet"}},
+				})
+			}))
+			defer server.Close()
+
+			provider, err := NewMemoryProvider(Config{SourceURL: server.URL}, logrus.New())
+			require.NoError(t, err)
+
+			err = provider.refresh(context.Background())
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unsupported schema version 2.0.0")
+				assert.Empty(t, provider.networks)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, provider.networks, "mainnet")
+		})
+	}
+}
@@ -304,6 +304,10 @@ func (r *RedisProvider) refresh(ctx context.Context) error {
 		return fmt.Errorf("decode response: %w", decodeErr)
 	}
 
+	if versionErr := checkSchemaVersion(result.SchemaVersion); versionErr != nil {
+		return fmt.Errorf("incompatible response: %w", versionErr)
+	}
+
 	// Store networks in Redis
 	networksJSON, err := json.Marshal(result.Networks)
 	if err != nil {
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// checkSchemaVersion returns an error if data with the given schema version
+// can't be read by this client, i.e. its major version differs from
+// discovery.SchemaVersion. Data published before versioning has no version
+// and is accepted.
+func checkSchemaVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+
+	major, err := discovery.SchemaMajor(version)
+	if err != nil {
+		return err
+	}
+
+	supported, err := discovery.SchemaMajor(discovery.SchemaVersion)
+	if err != nil {
+		return err
+	}
+
+	if major != supported {
+		return fmt.Errorf("unsupported schema version %s, this client supports %d.x", version, supported)
+	}
+
+	return nil
+}
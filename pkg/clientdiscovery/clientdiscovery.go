@@ -9,12 +9,16 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 
 	gh "github.com/google/go-github/v53/github"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/ethpandaops/cartographoor/pkg/githubapi"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
 )
 
 // Ensure Discoverer implements discovery.ClientDiscovererInterface.
@@ -22,30 +26,27 @@ var _ discovery.ClientDiscovererInterface = (*Discoverer)(nil)
 
 // Discoverer handles fetching information about Ethereum clients from GitHub.
 type Discoverer struct {
-	log    *logrus.Logger
-	client *gh.Client
+	log        *logrus.Logger
+	client     *gh.Client
+	rateLimits *ratelimit.Tracker
+
+	// versions holds the last known version of each client, reported while
+	// release lookups are skipped.
+	versionsMutex sync.Mutex
+	versions      map[string]string
 }
 
-// New creates a new client Discoverer. If token is empty, an unauthenticated
-// GitHub client is used (subject to stricter rate limits).
-func New(log *logrus.Logger, token string) *Discoverer {
+// New creates a new client Discoverer. If auth is nil, an unauthenticated
+// GitHub client is used (subject to stricter rate limits). cache and
+// rateLimits may be nil.
+func New(log *logrus.Logger, auth oauth2.TokenSource, cache *httpcache.Cache, rateLimits *ratelimit.Tracker) *Discoverer {
 	log = log.WithField("module", "client_discoverer").Logger
 
-	var client *gh.Client
-
-	if token != "" {
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: token},
-		)
-		tc := oauth2.NewClient(context.Background(), ts)
-		client = gh.NewClient(tc)
-	} else {
-		client = gh.NewClient(nil)
-	}
-
 	return &Discoverer{
-		log:    log,
-		client: client,
+		log:        log,
+		client:     githubapi.NewClient(auth, cache, rateLimits),
+		rateLimits: rateLimits,
+		versions:   make(map[string]string),
 	}
 }
 
@@ -55,6 +56,13 @@ func (d *Discoverer) DiscoverClients(ctx context.Context) (map[string]discovery.
 
 	clients := make(map[string]discovery.ClientInfo)
 
+	// Release lookups are low priority, skip them while the GitHub API budget
+	// is low and keep the versions found before.
+	skipLookups := d.rateLimits.Low()
+	if skipLookups {
+		d.log.Warn("GitHub API rate limit is low, skipping client release lookups")
+	}
+
 	// Process all clients
 	allClients := append(discovery.CLClients, discovery.ELClients...)
 
@@ -105,12 +113,7 @@ func (d *Discoverer) DiscoverClients(ctx context.Context) (map[string]discovery.
 		}
 
 		// Try to fetch the latest version
-		version, err := d.getLatestVersion(ctx, repo)
-		if err != nil {
-			d.log.WithError(err).WithField("client", clientName).Warn("Failed to get latest version")
-		} else {
-			clientInfo.LatestVersion = version
-		}
+		clientInfo.LatestVersion = d.latestVersion(ctx, clientName, repo, skipLookups)
 
 		clients[clientName] = clientInfo
 	}
@@ -120,6 +123,28 @@ func (d *Discoverer) DiscoverClients(ctx context.Context) (map[string]discovery.
 	return clients, nil
 }
 
+// latestVersion returns the latest version of a client, or the last known one
+// if the lookup is skipped or fails.
+func (d *Discoverer) latestVersion(ctx context.Context, clientName, repo string, skipLookup bool) string {
+	d.versionsMutex.Lock()
+	defer d.versionsMutex.Unlock()
+
+	if skipLookup {
+		return d.versions[clientName]
+	}
+
+	version, err := d.getLatestVersion(ctx, repo)
+	if err != nil {
+		d.log.WithError(err).WithField("client", clientName).Warn("Failed to get latest version")
+
+		return d.versions[clientName]
+	}
+
+	d.versions[clientName] = version
+
+	return version
+}
+
 // getLatestVersion fetches the latest released version of a client.
 func (d *Discoverer) getLatestVersion(ctx context.Context, repo string) (string, error) {
 	parts := strings.Split(repo, "/")
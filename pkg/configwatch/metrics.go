@@ -0,0 +1,24 @@
+package configwatch
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+)
+
+var (
+	reloads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "config",
+		Name:      "reloads_total",
+		Help:      "Config file reloads by result (success or failure).",
+	}, []string{"result"})
+
+	lastReloadTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "config",
+		Name:      "last_reload_success_timestamp_seconds",
+		Help:      "Unix time of the last successful config file reload.",
+	})
+)
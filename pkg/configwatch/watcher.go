@@ -0,0 +1,170 @@
+// Package configwatch watches a config file and reloads it when its content
+// changes. The file's directory is watched rather than the file itself, so
+// editors that replace the file and Kubernetes ConfigMap volumes, which swap a
+// symlink, are picked up as well.
+package configwatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDebounce is how long the watcher waits for further changes before
+// reloading, as editors and ConfigMap updates touch the file several times.
+const DefaultDebounce = 500 * time.Millisecond
+
+// ReloadFunc loads and applies the config file. If it returns an error, the
+// previous config is expected to stay in use.
+type ReloadFunc func() error
+
+// Watcher reloads a config file when its content changes.
+type Watcher struct {
+	log      logrus.FieldLogger
+	path     string
+	debounce time.Duration
+	reload   ReloadFunc
+
+	watcher *fsnotify.Watcher
+	content []byte
+	wg      sync.WaitGroup
+	cancel  context.CancelFunc
+}
+
+// New creates a watcher for the config file at path. The current content is
+// taken as already loaded, so only later changes trigger a reload.
+func New(log logrus.FieldLogger, path string, debounce time.Duration, reload ReloadFunc) (*Watcher, error) {
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config file path: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return &Watcher{
+		log:      log.WithField("module", "configwatch"),
+		path:     path,
+		debounce: debounce,
+		reload:   reload,
+		content:  content,
+	}, nil
+}
+
+// Start starts watching the config file.
+func (w *Watcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		_ = watcher.Close()
+
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	w.watcher = watcher
+
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	w.wg.Go(func() {
+		w.run(ctx)
+	})
+
+	w.log.WithField("path", w.path).Info("Watching config file for changes")
+
+	return nil
+}
+
+// Stop stops watching the config file.
+func (w *Watcher) Stop() error {
+	if w.cancel == nil {
+		return nil
+	}
+
+	w.cancel()
+	err := w.watcher.Close()
+	w.wg.Wait()
+
+	return err
+}
+
+// run debounces file system events and reloads once they settle.
+func (w *Watcher) run(ctx context.Context) {
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			// Any event in the directory may be a change of the file, such as
+			// the ..data symlink swap of a ConfigMap volume. The content
+			// comparison in check filters out unrelated files.
+			timer.Reset(w.debounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			w.log.WithError(err).Warn("Config file watcher error")
+		case <-timer.C:
+			w.check()
+		}
+	}
+}
+
+// check reloads the config file if its content changed since the last reload.
+func (w *Watcher) check() {
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		// The file may be briefly missing while it is replaced, a later event
+		// picks up the new file.
+		if !errors.Is(err, os.ErrNotExist) {
+			w.log.WithError(err).Warn("Failed to read config file")
+		}
+
+		return
+	}
+
+	if bytes.Equal(content, w.content) {
+		return
+	}
+
+	// Remember the content even if the reload fails, so the same invalid
+	// config isn't rejected again on every unrelated event.
+	w.content = content
+
+	if err := w.reload(); err != nil {
+		reloads.WithLabelValues("failure").Inc()
+		w.log.WithError(err).Error("Failed to reload config file, keeping the previous config")
+
+		return
+	}
+
+	reloads.WithLabelValues("success").Inc()
+	lastReloadTimestamp.Set(float64(time.Now().Unix()))
+	w.log.WithField("path", w.path).Info("Reloaded config file")
+}
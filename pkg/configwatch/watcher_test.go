@@ -0,0 +1,94 @@
+package configwatch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startWatcher writes initial to a config file and watches it with reload.
+func startWatcher(t *testing.T, initial string, reload ReloadFunc) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(initial), 0o600))
+
+	watcher, err := New(logrus.New(), path, 20*time.Millisecond, reload)
+	require.NoError(t, err)
+	require.NoError(t, watcher.Start(context.Background()))
+
+	t.Cleanup(func() {
+		assert.NoError(t, watcher.Stop())
+	})
+
+	return path
+}
+
+func TestWatcher_ReloadsOnChange(t *testing.T) {
+	var calls atomic.Int32
+
+	path := startWatcher(t, "interval: 1h\n", func() error {
+		calls.Add(1)
+
+		return nil
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("interval: 2h\n"), 0o600))
+
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	// Unchanged content and unrelated files don't reload.
+	require.NoError(t, os.WriteFile(path, []byte("interval: 2h\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), "other.yaml"), []byte("x"), 0o600))
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWatcher_ReloadsOnReplace(t *testing.T) {
+	var calls atomic.Int32
+
+	path := startWatcher(t, "interval: 1h\n", func() error {
+		calls.Add(1)
+
+		return nil
+	})
+
+	// Editors and ConfigMap volumes replace the file rather than writing it.
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte("interval: 3h\n"), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWatcher_FailedReloadIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+
+	path := startWatcher(t, "interval: 1h\n", func() error {
+		calls.Add(1)
+
+		return errors.New("invalid config")
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("interval: -1h\n"), 0o600))
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), "other.yaml"), []byte("x"), 0o600))
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestNew_MissingFile(t *testing.T) {
+	_, err := New(logrus.New(), filepath.Join(t.TempDir(), "missing.yaml"), 0, func() error { return nil })
+	require.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package discovery
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default concurrency of GitHub discovery.
+const (
+	DefaultConcurrentRepositories = 4
+	DefaultConcurrentNetworks     = 16
+	DefaultConcurrentPerHost      = 8
+	DefaultNetworkTimeout         = 2 * time.Minute
+)
+
+// GitHubConcurrencyConfig bounds the concurrency of GitHub discovery.
+type GitHubConcurrencyConfig struct {
+	// Repositories is the number of repositories discovered at once.
+	Repositories int `mapstructure:"repositories"`
+
+	// Networks is the number of networks processed at once, across all
+	// repositories.
+	Networks int `mapstructure:"networks"`
+
+	// PerHost is the number of concurrent requests to a single host, such as
+	// the GitHub API or a service URL probe.
+	PerHost int `mapstructure:"perHost"`
+
+	// NetworkTimeout bounds processing a single network. Details not fetched
+	// in time are left out of the network.
+	NetworkTimeout time.Duration `mapstructure:"networkTimeout"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *GitHubConcurrencyConfig) SetDefaults() {
+	if c.Repositories == 0 {
+		c.Repositories = DefaultConcurrentRepositories
+	}
+
+	if c.Networks == 0 {
+		c.Networks = DefaultConcurrentNetworks
+	}
+
+	if c.PerHost == 0 {
+		c.PerHost = DefaultConcurrentPerHost
+	}
+
+	if c.NetworkTimeout == 0 {
+		c.NetworkTimeout = DefaultNetworkTimeout
+	}
+}
+
+// Validate validates the config.
+func (c *GitHubConcurrencyConfig) Validate() error {
+	if c.Repositories < 0 {
+		return fmt.Errorf("repositories must not be negative, got %d", c.Repositories)
+	}
+
+	if c.Networks < 0 {
+		return fmt.Errorf("networks must not be negative, got %d", c.Networks)
+	}
+
+	if c.PerHost < 0 {
+		return fmt.Errorf("perHost must not be negative, got %d", c.PerHost)
+	}
+
+	if c.NetworkTimeout < 0 {
+		return fmt.Errorf("networkTimeout must not be negative, got %s", c.NetworkTimeout)
+	}
+
+	return nil
+}
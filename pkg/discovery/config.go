@@ -0,0 +1,141 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultInterval is the discovery interval when none is configured.
+const DefaultInterval = 1 * time.Hour
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+
+	if c.StaleGracePeriod == 0 {
+		c.StaleGracePeriod = DefaultStaleGracePeriod
+	}
+
+	if c.TriggerDebounce == 0 {
+		c.TriggerDebounce = DefaultTriggerDebounce
+	}
+
+	c.GitHub.Concurrency.SetDefaults()
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("interval must not be negative, got %s", c.Interval)
+	}
+
+	seen := make(map[string]bool, len(c.GitHub.Repositories))
+
+	for _, repo := range c.GitHub.Repositories {
+		if owner, name, ok := strings.Cut(repo.Name, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("github repository %q must be in owner/repo form", repo.Name)
+		}
+
+		if seen[strings.ToLower(repo.Name)] {
+			return fmt.Errorf("github repository %s is configured twice", repo.Name)
+		}
+
+		seen[strings.ToLower(repo.Name)] = true
+
+		if err := repo.Paths.Validate(); err != nil {
+			return fmt.Errorf("github repository %s: %w", repo.Name, err)
+		}
+	}
+
+	if err := c.GitHub.Concurrency.Validate(); err != nil {
+		return fmt.Errorf("github concurrency: %w", err)
+	}
+
+	for i, source := range c.GitHub.Sources {
+		if err := source.Validate(); err != nil {
+			return fmt.Errorf("github source %d: %w", i, err)
+		}
+	}
+
+	names := make(map[string]bool, len(c.Static.Networks))
+
+	for _, network := range c.Static.Networks {
+		if network.Name == "" {
+			return errors.New("static network without a name")
+		}
+
+		if names[network.Name] {
+			return fmt.Errorf("static network %s is configured twice", network.Name)
+		}
+
+		names[network.Name] = true
+
+		var consensusForks map[string]ConsensusForkConfig
+		if network.Forks != nil {
+			consensusForks = network.Forks.Consensus
+		}
+
+		for _, timing := range network.SlotTiming {
+			if _, ok := consensusForks[timing.Fork]; !ok {
+				return fmt.Errorf("static network %s: slot timing of unknown consensus fork %q", network.Name, timing.Fork)
+			}
+		}
+	}
+
+	return c.Merge.Validate()
+}
+
+// UpdateConfig validates cfg and atomically replaces the config used by
+// subsequent discovery runs; a run in progress finishes with the previous
+// config. On error the previous config is kept. Providers are created once at
+// startup, so changes to discovery.providers need a restart.
+func (s *Service) UpdateConfig(cfg Config) error {
+	cfg.SetDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid discovery config: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous := s.config
+
+	// Providers can't be swapped at runtime, keep the ones they were created from.
+	providersChanged := fmt.Sprint(previous.Providers) != fmt.Sprint(cfg.Providers)
+	cfg.Providers = previous.Providers
+
+	s.config = cfg
+
+	if cfg.Interval != previous.Interval && s.ticker != nil {
+		s.ticker.Reset(cfg.Interval)
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"interval":        cfg.Interval,
+		"staticNetworks":  len(cfg.Static.Networks),
+		"githubRepos":     len(cfg.GitHub.Repositories),
+		"githubSources":   len(cfg.GitHub.Sources),
+		"intervalChanged": cfg.Interval != previous.Interval,
+	}).Info("Updated discovery config")
+
+	if providersChanged {
+		s.log.Warn("Changes to discovery.providers are ignored until restart")
+	}
+
+	return nil
+}
+
+// currentConfig returns the config for a discovery run.
+func (s *Service) currentConfig() Config {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.config
+}
@@ -0,0 +1,191 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	withRepos := func(repos ...string) Config {
+		var config Config
+		for _, repo := range repos {
+			config.GitHub.Repositories = append(config.GitHub.Repositories, GitHubRepositoryConfig{Name: repo})
+		}
+
+		return config
+	}
+
+	withRepo := func(repo GitHubRepositoryConfig) Config {
+		var config Config
+		config.GitHub.Repositories = []GitHubRepositoryConfig{repo}
+
+		return config
+	}
+
+	withStatic := func(names ...string) Config {
+		var config Config
+		for _, name := range names {
+			config.Static.Networks = append(config.Static.Networks, StaticNetworkConfig{Name: name})
+		}
+
+		return config
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: withRepos("org/a", "org/b"),
+		},
+		{
+			name:    "negative interval",
+			config:  Config{Interval: -time.Minute},
+			wantErr: "interval must not be negative",
+		},
+		{
+			name:    "malformed repository",
+			config:  withRepos("org"),
+			wantErr: "owner/repo form",
+		},
+		{
+			name:    "duplicate repository",
+			config:  withRepos("org/a", "Org/A"),
+			wantErr: "configured twice",
+		},
+		{
+			name: "custom layout",
+			config: withRepo(GitHubRepositoryConfig{
+				Name:  "org/a",
+				Ref:   "main",
+				Paths: RepositoryPaths{NetworkConfigs: "configs", Active: "deploy/{network}"},
+			}),
+		},
+		{
+			name: "absolute path",
+			config: withRepo(GitHubRepositoryConfig{
+				Name:  "org/a",
+				Paths: RepositoryPaths{ValuesFile: "/etc/values.yaml"},
+			}),
+			wantErr: "paths.valuesFile must be a clean path",
+		},
+		{
+			name: "network configs per network",
+			config: withRepo(GitHubRepositoryConfig{
+				Name:  "org/a",
+				Paths: RepositoryPaths{NetworkConfigs: "configs/{network}"},
+			}),
+			wantErr: "paths.networkConfigs must not contain {network}",
+		},
+		{
+			name:    "unnamed static network",
+			config:  withStatic(""),
+			wantErr: "static network without a name",
+		},
+		{
+			name:    "duplicate static network",
+			config:  withStatic("mainnet", "mainnet"),
+			wantErr: "static network mainnet is configured twice",
+		},
+		{
+			name: "slot timing of unknown fork",
+			config: Config{Static: struct {
+				Networks []StaticNetworkConfig `mapstructure:"networks"`
+			}{Networks: []StaticNetworkConfig{{
+				Name:       "mainnet",
+				Forks:      &ForksConfig{Consensus: map[string]ConsensusForkConfig{"fulu": {Epoch: 10}}},
+				SlotTiming: []StaticSlotTimingConfig{{Fork: "gloas", SlotDurationSeconds: 6}},
+			}}}},
+			wantErr: `static network mainnet: slot timing of unknown consensus fork "gloas"`,
+		},
+		{
+			name: "negative concurrency",
+			config: Config{GitHub: GitHubConfig{
+				Concurrency: GitHubConcurrencyConfig{Networks: -1},
+			}},
+			wantErr: "github concurrency: networks must not be negative",
+		},
+		{
+			name:    "unknown merge field",
+			config:  Config{Merge: MergeConfig{Fields: map[string][]string{"nope": {"static"}}}},
+			wantErr: "unknown network field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestService_UpdateConfig(t *testing.T) {
+	service, provider, results := newTriggerTestService(t)
+
+	next := service.currentConfig()
+	next.GitHub.Repositories = append(next.GitHub.Repositories, GitHubRepositoryConfig{Name: "org/c"})
+	next.Interval = 2 * time.Hour
+
+	require.NoError(t, service.UpdateConfig(next))
+	assert.Equal(t, 2*time.Hour, service.Interval())
+
+	// The next run uses the new repositories.
+	assert.True(t, service.Trigger("test"))
+
+	result := waitForResult(t, results)
+	assert.Contains(t, result.Networks, "org/c")
+	assert.Equal(t, 2, provider.runs)
+
+	// Newly configured repositories can be triggered.
+	assert.True(t, service.Trigger("test", "org/c"))
+	waitForResult(t, results)
+}
+
+func TestService_UpdateConfigRejectsInvalid(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{Interval: time.Hour}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = service.Stop(context.Background())
+	})
+
+	require.NoError(t, service.Start(ctx))
+
+	invalid := Config{Interval: time.Minute}
+	invalid.GitHub.Repositories = []GitHubRepositoryConfig{{Name: "not-a-repo"}}
+
+	require.Error(t, service.UpdateConfig(invalid))
+	assert.Equal(t, time.Hour, service.Interval())
+}
+
+func TestService_UpdateConfigKeepsProviders(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{
+		Providers: map[string]ProviderConfig{"static": {Priority: 1}},
+	}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, service.UpdateConfig(Config{
+		Providers: map[string]ProviderConfig{"github": {}},
+	}))
+
+	config := service.currentConfig()
+	assert.Equal(t, map[string]ProviderConfig{"static": {Priority: 1}}, config.Providers)
+	assert.Equal(t, DefaultInterval, config.Interval)
+}
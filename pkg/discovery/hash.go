@@ -0,0 +1,81 @@
+package discovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// networkVersion is the content hash of a network and when that content was
+// first seen.
+type networkVersion struct {
+	hash        string
+	lastUpdated time.Time
+}
+
+// HashNetwork returns a stable hash of a network's content. Fields that do not
+// describe the network itself (LastUpdated, Hash and Stale) are ignored, so the
+// hash only changes when the network does.
+func HashNetwork(network Network) (string, error) {
+	network.LastUpdated = time.Time{}
+	network.Hash = ""
+	network.Stale = nil
+
+	// encoding/json writes struct fields in declaration order and map keys
+	// sorted, which makes the output canonical.
+	data, err := json.Marshal(network)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal network %s: %w", network.Name, err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// stampNetworks sets the Hash of every network and carries LastUpdated forward
+// from the previous version of the network if its content did not change.
+func (s *Service) stampNetworks(networks map[string]Network, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	versions := make(map[string]networkVersion, len(networks))
+
+	for name, network := range networks {
+		hash, err := HashNetwork(network)
+		if err != nil {
+			s.log.WithError(err).WithField("network", name).Warn("Failed to hash network")
+		}
+
+		network.Hash = hash
+		network.LastUpdated = now
+
+		if prev, ok := s.versions[name]; ok && hash != "" && prev.hash == hash && !prev.lastUpdated.IsZero() {
+			network.LastUpdated = prev.lastUpdated
+		}
+
+		networks[name] = network
+		versions[name] = networkVersion{hash: hash, lastUpdated: network.LastUpdated}
+	}
+
+	s.versions = versions
+}
+
+// seedVersions records the versions of previously published networks. Networks
+// published before hashing was introduced are hashed from their content.
+func (s *Service) seedVersions(networks map[string]Network) {
+	for name, network := range networks {
+		hash := network.Hash
+		if hash == "" {
+			var err error
+
+			if hash, err = HashNetwork(network); err != nil {
+				continue
+			}
+		}
+
+		s.versions[name] = networkVersion{hash: hash, lastUpdated: network.LastUpdated}
+	}
+}
@@ -0,0 +1,102 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashNetwork(t *testing.T) {
+	network := Network{
+		Name:   "devnet-1",
+		Status: "active",
+		Forks: &ForksConfig{
+			Consensus: map[string]ConsensusForkConfig{
+				"electra": {Epoch: 10},
+				"fulu":    {Epoch: 20},
+			},
+		},
+	}
+
+	hash, err := HashNetwork(network)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	// Metadata fields don't affect the hash.
+	withMetadata := network
+	withMetadata.LastUpdated = time.Now()
+	withMetadata.Hash = "previous"
+	withMetadata.Stale = &StaleInfo{Since: time.Now(), Reason: "rate limited"}
+
+	metadataHash, err := HashNetwork(withMetadata)
+	require.NoError(t, err)
+	assert.Equal(t, hash, metadataHash)
+
+	// Content does.
+	changed := network
+	changed.Status = "inactive"
+
+	changedHash, err := HashNetwork(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestLastUpdated_OnlyMovesOnChange(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	provider := NewMockProvider("github", map[string]Network{
+		"devnet-1": {Name: "devnet-1", Status: "active"},
+		"devnet-2": {Name: "devnet-2", Status: "active"},
+	}, nil)
+	service.RegisterProvider(provider)
+
+	first, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, first.Networks["devnet-1"].Hash)
+	require.False(t, first.Networks["devnet-1"].LastUpdated.IsZero())
+
+	provider.networks = map[string]Network{
+		"devnet-1": {Name: "devnet-1", Status: "active"},
+		"devnet-2": {Name: "devnet-2", Status: "inactive"},
+	}
+
+	second, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Networks["devnet-1"].Hash, second.Networks["devnet-1"].Hash)
+	assert.Equal(t, first.Networks["devnet-1"].LastUpdated, second.Networks["devnet-1"].LastUpdated)
+
+	assert.NotEqual(t, first.Networks["devnet-2"].Hash, second.Networks["devnet-2"].Hash)
+	assert.True(t, second.Networks["devnet-2"].LastUpdated.After(first.Networks["devnet-2"].LastUpdated))
+}
+
+func TestLastUpdated_CarriedForwardFromSeed(t *testing.T) {
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	// Networks published without a hash are hashed from their content.
+	service.Seed(Result{
+		Networks: map[string]Network{
+			"devnet-1": {Name: "devnet-1", Status: "active", LastUpdated: published},
+			"devnet-2": {Name: "devnet-2", Status: "active", LastUpdated: published},
+		},
+	})
+
+	service.RegisterProvider(NewMockProvider("github", map[string]Network{
+		"devnet-1": {Name: "devnet-1", Status: "active"},
+		"devnet-2": {Name: "devnet-2", Status: "inactive"},
+	}, nil))
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, published, result.Networks["devnet-1"].LastUpdated)
+	assert.True(t, result.Networks["devnet-2"].LastUpdated.After(published))
+}
@@ -0,0 +1,94 @@
+package discovery
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// NetworkPlaceholder is replaced by the network name in RepositoryPaths.
+const NetworkPlaceholder = "{network}"
+
+// Default directory layout of a network repository.
+const (
+	DefaultNetworkConfigsPath = "network-configs"
+	DefaultActivePath         = "kubernetes/" + NetworkPlaceholder
+	DefaultArchivedPath       = "kubernetes-archive/" + NetworkPlaceholder
+	DefaultValuesFilePath     = "kubernetes/" + NetworkPlaceholder + "/config/values.yaml"
+	DefaultImagesFilePath     = "ansible/inventories/" + NetworkPlaceholder + "/group_vars/all/images.yaml"
+	DefaultInventoriesPath    = "ansible/inventories/" + NetworkPlaceholder
+)
+
+// RepositoryPaths is the directory layout of a network repository. Paths are
+// relative to the repository root, and all but NetworkConfigs are templates in
+// which NetworkPlaceholder is replaced by the network name.
+type RepositoryPaths struct {
+	// NetworkConfigs is the directory with a subdirectory per network.
+	NetworkConfigs string `mapstructure:"networkConfigs"`
+	// Active is the directory that marks a network as active.
+	Active string `mapstructure:"active"`
+	// Archived is the directory that marks a network as inactive.
+	Archived string `mapstructure:"archived"`
+	// ValuesFile is the values.yaml with the domain and config files of an
+	// active network.
+	ValuesFile string `mapstructure:"valuesFile"`
+	// ImagesFile is the images.yaml with the client and tool images.
+	ImagesFile string `mapstructure:"imagesFile"`
+	// Inventories is the directory with the ansible inventories of a network.
+	Inventories string `mapstructure:"inventories"`
+}
+
+// WithDefaults returns the paths with the default layout for those not set.
+func (p RepositoryPaths) WithDefaults() RepositoryPaths {
+	defaults := []struct {
+		value *string
+		def   string
+	}{
+		{&p.NetworkConfigs, DefaultNetworkConfigsPath},
+		{&p.Active, DefaultActivePath},
+		{&p.Archived, DefaultArchivedPath},
+		{&p.ValuesFile, DefaultValuesFilePath},
+		{&p.ImagesFile, DefaultImagesFilePath},
+		{&p.Inventories, DefaultInventoriesPath},
+	}
+
+	for _, d := range defaults {
+		if *d.value == "" {
+			*d.value = d.def
+		}
+	}
+
+	return p
+}
+
+// Validate validates the paths that are set.
+func (p RepositoryPaths) Validate() error {
+	for _, field := range []struct{ name, value string }{
+		{"networkConfigs", p.NetworkConfigs},
+		{"active", p.Active},
+		{"archived", p.Archived},
+		{"valuesFile", p.ValuesFile},
+		{"imagesFile", p.ImagesFile},
+		{"inventories", p.Inventories},
+	} {
+		name, value := field.name, field.value
+		if value == "" {
+			continue
+		}
+
+		if path.IsAbs(value) || path.Clean(value) != value || value == "." || strings.HasPrefix(value, "..") {
+			return fmt.Errorf("paths.%s must be a clean path relative to the repository root, got %q", name, value)
+		}
+	}
+
+	if strings.Contains(p.NetworkConfigs, NetworkPlaceholder) {
+		return fmt.Errorf("paths.networkConfigs must not contain %s", NetworkPlaceholder)
+	}
+
+	return nil
+}
+
+// ForNetwork returns a path template with the network name filled in.
+func ForNetwork(template, network string) string {
+	return strings.ReplaceAll(template, NetworkPlaceholder, network)
+}
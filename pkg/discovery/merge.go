@@ -0,0 +1,140 @@
+package discovery
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ethpandaops/cartographoor/pkg/jsonschema"
+)
+
+// MergeConfig configures how a network discovered by several providers is
+// merged.
+type MergeConfig struct {
+	// Fields maps a network field, by its JSON name such as "forks" or
+	// "serviceUrls", to the providers it is taken from in order of preference.
+	// The first listed provider that discovered the network with a non-empty
+	// value wins. Other fields come from the provider with the highest priority.
+	Fields map[string][]string `mapstructure:"fields"`
+}
+
+// MergeConflict records a network that was discovered by several providers.
+type MergeConflict struct {
+	Network string `json:"network"`
+	// Providers that discovered the network, in merge order.
+	Providers []string `json:"providers"`
+	// Winner is the provider the network was taken from.
+	Winner string `json:"winner"`
+	// Fields maps the fields taken from another provider by a merge rule to
+	// that provider.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Differing lists the fields whose values differ between the providers.
+	Differing []string `json:"differing,omitempty"`
+}
+
+// unmergedFields are set by the discovery service rather than providers.
+var unmergedFields = []string{"name", "lastUpdated", "hash", "stale"}
+
+// networkFields maps the lower-cased JSON name of each Network field to its
+// index, so merge rules can be matched regardless of how viper cased the keys.
+var networkFields = func() map[string]int {
+	fields := make(map[string]int)
+
+	t := reflect.TypeFor[Network]()
+	for i := range t.NumField() {
+		name, _, ok := jsonschema.FieldName(t.Field(i))
+		if ok && !slices.Contains(unmergedFields, name) {
+			fields[strings.ToLower(name)] = i
+		}
+	}
+
+	return fields
+}()
+
+// Validate validates the merge rules.
+func (c *MergeConfig) Validate() error {
+	for field := range c.Fields {
+		if _, ok := networkFields[strings.ToLower(field)]; !ok {
+			return fmt.Errorf("unknown network field %q in merge rules", field)
+		}
+	}
+
+	return nil
+}
+
+// mergeCandidate is a network as discovered by one provider.
+type mergeCandidate struct {
+	provider string
+	network  Network
+}
+
+// mergeNetwork merges the candidates of a network, given in ascending
+// priority. The last candidate wins, except for fields with a merge rule.
+func mergeNetwork(name string, candidates []mergeCandidate, rules MergeConfig) (Network, *MergeConflict) {
+	winner := candidates[len(candidates)-1]
+	if len(candidates) == 1 {
+		return winner.network, nil
+	}
+
+	conflict := &MergeConflict{
+		Network:   name,
+		Providers: make([]string, 0, len(candidates)),
+		Winner:    winner.provider,
+		Fields:    make(map[string]string),
+	}
+
+	for _, candidate := range candidates {
+		conflict.Providers = append(conflict.Providers, candidate.provider)
+	}
+
+	merged := winner.network
+	mergedValue := reflect.ValueOf(&merged).Elem()
+
+	for field, providers := range rules.Fields {
+		index := networkFields[strings.ToLower(field)]
+
+		for _, provider := range providers {
+			i := slices.IndexFunc(candidates, func(c mergeCandidate) bool { return c.provider == provider })
+			if i < 0 {
+				continue
+			}
+
+			value := reflect.ValueOf(candidates[i].network).Field(index)
+			if value.IsZero() {
+				continue
+			}
+
+			mergedValue.Field(index).Set(value)
+
+			if provider != winner.provider {
+				conflict.Fields[jsonFieldName(index)] = provider
+			}
+
+			break
+		}
+	}
+
+	first := reflect.ValueOf(candidates[0].network)
+
+	for _, index := range networkFields {
+		for _, candidate := range candidates[1:] {
+			if !reflect.DeepEqual(first.Field(index).Interface(), reflect.ValueOf(candidate.network).Field(index).Interface()) {
+				conflict.Differing = append(conflict.Differing, jsonFieldName(index))
+
+				break
+			}
+		}
+	}
+
+	slices.Sort(conflict.Differing)
+
+	return merged, conflict
+}
+
+// jsonFieldName returns the JSON name of the Network field at index.
+func jsonFieldName(index int) string {
+	name, _, _ := jsonschema.FieldName(reflect.TypeFor[Network]().Field(index))
+
+	return name
+}
@@ -0,0 +1,124 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeNetworks(t *testing.T) {
+	githubNetwork := Network{
+		Name:        "devnet-1",
+		Repository:  "ethpandaops/devnets",
+		Status:      "active",
+		ServiceURLs: &ServiceURLs{Dora: "https://dora.devnet-1.example.com"},
+		Forks:       &ForksConfig{Consensus: map[string]ConsensusForkConfig{"fulu": {Epoch: 100}}},
+	}
+
+	staticNetwork := Network{
+		Name:        "devnet-1",
+		Description: "Static devnet",
+		Status:      "active",
+		Forks:       &ForksConfig{Consensus: map[string]ConsensusForkConfig{"fulu": {Epoch: 200}}},
+	}
+
+	tests := []struct {
+		name     string
+		config   Config
+		priority int
+		check    func(t *testing.T, network Network, conflict MergeConflict)
+	}{
+		{
+			name:     "highest priority wins",
+			priority: 10,
+			check: func(t *testing.T, network Network, conflict MergeConflict) {
+				t.Helper()
+
+				assert.Equal(t, "ethpandaops/devnets", network.Repository)
+				assert.Equal(t, "github", conflict.Winner)
+				assert.Equal(t, []string{"static", "github"}, conflict.Providers)
+				assert.Empty(t, conflict.Fields)
+			},
+		},
+		{
+			name: "registration order breaks ties",
+			check: func(t *testing.T, network Network, conflict MergeConflict) {
+				t.Helper()
+
+				assert.Equal(t, "Static devnet", network.Description)
+				assert.Equal(t, "static", conflict.Winner)
+				assert.Equal(t, []string{"github", "static"}, conflict.Providers)
+			},
+		},
+		{
+			name: "field rules",
+			config: Config{
+				Merge: MergeConfig{Fields: map[string][]string{
+					// viper lower-cases keys
+					"serviceurls": {"github"},
+					"forks":       {"static", "github"},
+				}},
+			},
+			priority: 10,
+			check: func(t *testing.T, network Network, conflict MergeConflict) {
+				t.Helper()
+
+				assert.Equal(t, "github", conflict.Winner)
+				assert.Equal(t, uint64(200), network.Forks.Consensus["fulu"].Epoch)
+				assert.Equal(t, "https://dora.devnet-1.example.com", network.ServiceURLs.Dora)
+				assert.Equal(t, map[string]string{"forks": "static"}, conflict.Fields)
+			},
+		},
+		{
+			name: "field rules skip empty values",
+			config: Config{
+				Merge: MergeConfig{Fields: map[string][]string{
+					"serviceUrls": {"static", "github"},
+				}},
+			},
+			check: func(t *testing.T, network Network, conflict MergeConflict) {
+				t.Helper()
+
+				assert.Equal(t, "static", conflict.Winner)
+				require.NotNil(t, network.ServiceURLs)
+				assert.Equal(t, map[string]string{"serviceUrls": "github"}, conflict.Fields)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, err := NewService(logrus.New(), tt.config, nil)
+			require.NoError(t, err)
+
+			service.RegisterProviderWithOptions(NewMockProvider("github", map[string]Network{"devnet-1": githubNetwork}, nil), ProviderOptions{Priority: tt.priority})
+			service.RegisterProvider(NewMockProvider("static", map[string]Network{
+				"devnet-1": staticNetwork,
+				"mainnet":  {Name: "mainnet"},
+			}, nil))
+
+			result, err := service.RunOnce(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, result.Networks, 2)
+			require.Len(t, result.Conflicts, 1, "networks found by one provider are not conflicts")
+
+			conflict := result.Conflicts[0]
+			assert.Equal(t, "devnet-1", conflict.Network)
+			assert.Equal(t, []string{"description", "forks", "repository", "serviceUrls"}, conflict.Differing)
+
+			tt.check(t, result.Networks["devnet-1"], conflict)
+		})
+	}
+}
+
+func TestMergeConfig_Validate(t *testing.T) {
+	_, err := NewService(logrus.New(), Config{Merge: MergeConfig{Fields: map[string][]string{"lastUpdated": {"static"}}}}, nil)
+	assert.ErrorContains(t, err, `unknown network field "lastUpdated"`)
+
+	_, err = NewService(logrus.New(), Config{Merge: MergeConfig{Fields: map[string][]string{"blobSchedule": {"static"}}}}, nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,147 @@
+package discovery
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+)
+
+var (
+	runDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "discovery",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of discovery runs.",
+		Buckets:   metrics.DefBuckets,
+	})
+
+	lastRunTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "discovery",
+		Name:      "last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed discovery run.",
+	})
+
+	staleNetworks = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "discovery",
+		Name:      "stale_networks",
+		Help:      "Networks in the last discovery result reused from a previous run.",
+	})
+
+	providerRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "discovery",
+		Name:      "provider_runs_total",
+		Help:      "Discovery provider runs by provider and status (success, partial or failed).",
+	}, []string{"provider", "status"})
+
+	providerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "discovery",
+		Name:      "provider_duration_seconds",
+		Help:      "Duration of discovery provider runs.",
+		Buckets:   metrics.DefBuckets,
+	}, []string{"provider"})
+)
+
+// networkCounts exports the network counts of the last discovery result. The
+// counts are swapped as a whole, so a scrape never sees a partial snapshot or
+// the label sets of networks that have since disappeared.
+var networkCounts = func() *networksCollector {
+	c := &networksCollector{
+		byStatusDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "discovery", "networks"),
+			"Networks in the last discovery result by status.",
+			[]string{"status"}, nil,
+		),
+		byRepositoryDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "discovery", "repository_networks"),
+			"Networks in the last discovery result by repository and status. Static networks have an empty repository.",
+			[]string{"repository", "status"}, nil,
+		),
+	}
+	prometheus.MustRegister(c)
+
+	return c
+}()
+
+// repositoryStatus is a label set of the per-repository network count.
+type repositoryStatus struct {
+	repository string
+	status     string
+}
+
+// networksCollector is a prometheus.Collector serving the network counts of
+// the last discovery result.
+type networksCollector struct {
+	byStatusDesc     *prometheus.Desc
+	byRepositoryDesc *prometheus.Desc
+
+	mutex        sync.Mutex
+	byStatus     map[string]int
+	byRepository map[repositoryStatus]int
+}
+
+// Describe implements prometheus.Collector.
+func (c *networksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.byStatusDesc
+	ch <- c.byRepositoryDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *networksCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for status, count := range c.byStatus {
+		ch <- prometheus.MustNewConstMetric(c.byStatusDesc, prometheus.GaugeValue, float64(count), status)
+	}
+
+	for key, count := range c.byRepository {
+		ch <- prometheus.MustNewConstMetric(c.byRepositoryDesc, prometheus.GaugeValue, float64(count), key.repository, key.status)
+	}
+}
+
+// set replaces the counts with those of result.
+func (c *networksCollector) set(result Result) {
+	byStatus := make(map[string]int)
+	byRepository := make(map[repositoryStatus]int)
+
+	for _, network := range result.Networks {
+		byStatus[network.Status]++
+		byRepository[repositoryStatus{repository: network.Repository, status: network.Status}]++
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.byStatus = byStatus
+	c.byRepository = byRepository
+}
+
+// observeResult records the metrics of a completed discovery run.
+func observeResult(result Result) {
+	runDuration.Observe(result.Duration)
+	lastRunTimestamp.Set(float64(result.LastUpdate.Unix()))
+
+	for _, info := range result.Providers {
+		providerRuns.WithLabelValues(info.Name, info.Status).Inc()
+		providerDuration.WithLabelValues(info.Name).Observe(info.Duration)
+	}
+
+	networkCounts.set(result)
+
+	stale := 0
+
+	for _, network := range result.Networks {
+		if network.Stale != nil {
+			stale++
+		}
+	}
+
+	staleNetworks.Set(float64(stale))
+}
@@ -0,0 +1,56 @@
+package discovery
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveResult(t *testing.T) {
+	observeResult(Result{
+		Networks: map[string]Network{
+			"mainnet":  {Name: "mainnet", Status: "active"},
+			"devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "active"},
+			"devnet-2": {Name: "devnet-2", Repository: "ethpandaops/fusaka-devnets", Status: "inactive", Stale: &StaleInfo{}},
+		},
+		Providers: []ProviderInfo{
+			{Name: "github", Status: ProviderStatusPartial},
+		},
+	})
+
+	require.NoError(t, testutil.CollectAndCompare(networkCounts, strings.NewReader(`
+# HELP cartographoor_discovery_networks Networks in the last discovery result by status.
+# TYPE cartographoor_discovery_networks gauge
+cartographoor_discovery_networks{status="active"} 2
+cartographoor_discovery_networks{status="inactive"} 1
+# HELP cartographoor_discovery_repository_networks Networks in the last discovery result by repository and status. Static networks have an empty repository.
+# TYPE cartographoor_discovery_repository_networks gauge
+cartographoor_discovery_repository_networks{repository="",status="active"} 1
+cartographoor_discovery_repository_networks{repository="ethpandaops/fusaka-devnets",status="active"} 1
+cartographoor_discovery_repository_networks{repository="ethpandaops/fusaka-devnets",status="inactive"} 1
+`)))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(staleNetworks), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(providerRuns.WithLabelValues("github", ProviderStatusPartial)), 0)
+
+	// Label sets of networks that disappeared are dropped with the next result.
+	observeResult(Result{
+		Networks: map[string]Network{
+			"mainnet": {Name: "mainnet", Status: "active"},
+		},
+	})
+
+	require.NoError(t, testutil.CollectAndCompare(networkCounts, strings.NewReader(`
+# HELP cartographoor_discovery_networks Networks in the last discovery result by status.
+# TYPE cartographoor_discovery_networks gauge
+cartographoor_discovery_networks{status="active"} 1
+# HELP cartographoor_discovery_repository_networks Networks in the last discovery result by repository and status. Static networks have an empty repository.
+# TYPE cartographoor_discovery_repository_networks gauge
+cartographoor_discovery_repository_networks{repository="",status="active"} 1
+`)))
+
+	assert.InDelta(t, 0, testutil.ToFloat64(staleNetworks), 0)
+}
@@ -0,0 +1,163 @@
+package discovery
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
+)
+
+// ProviderDeps are the shared dependencies passed to provider factories.
+type ProviderDeps struct {
+	HTTPClient *http.Client
+	// GitHubAuth authenticates GitHub API calls, it may be nil. Providers then
+	// fall back to discovery.github.token.
+	GitHubAuth oauth2.TokenSource
+	// HTTPCache revalidates GitHub API and raw content fetches, it may be nil.
+	HTTPCache *httpcache.Cache
+	// RateLimits tracks the GitHub API rate limits, it may be nil. Providers
+	// skip low-priority work while the budget is low.
+	RateLimits *ratelimit.Tracker
+}
+
+// ProviderFactory creates a provider. config is the provider's own section,
+// discovery.providers.<name>.config, which is nil if not set. Factories decode
+// it with DecodeProviderConfig.
+type ProviderFactory func(log *logrus.Logger, deps ProviderDeps, config map[string]any) (Provider, error)
+
+// DecodeProviderConfig decodes the config section of a provider into target,
+// a pointer to a struct with mapstructure tags. Durations may be given as
+// strings such as "5s". Keys without a field are an error, so misspelled
+// options aren't silently ignored.
+func DecodeProviderConfig(config map[string]any, target any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:  mapstructure.StringToTimeDurationHookFunc(),
+		ErrorUnused: true,
+		Result:      target,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := decoder.Decode(config); err != nil {
+		return fmt.Errorf("invalid provider config: %w", err)
+	}
+
+	return nil
+}
+
+// ProviderConfig enables a discovery provider and configures how it runs.
+type ProviderConfig struct {
+	// Enabled defaults to true for providers listed in discovery.providers.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Priority decides which provider wins when several discover a network with
+	// the same name. Higher priorities win; configured providers are registered
+	// in name order, so on a tie the name that sorts last wins.
+	Priority int `mapstructure:"priority"`
+
+	// Timeout bounds a single Discover call. Zero means no timeout beyond the
+	// discovery run's own.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Config is the provider's own configuration, decoded by its factory.
+	Config map[string]any `mapstructure:"config"`
+}
+
+// IsEnabled returns true unless the provider is explicitly disabled.
+func (c ProviderConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+var (
+	factoriesMutex sync.RWMutex
+	factories      = make(map[string]ProviderFactory)
+)
+
+// RegisterProviderFactory makes a provider available under name. It is meant
+// to be called from the init function of provider packages, and panics if the
+// name is already registered.
+func RegisterProviderFactory(name string, factory ProviderFactory) {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+
+	if _, exists := factories[name]; exists {
+		panic("discovery: provider factory registered twice: " + name)
+	}
+
+	factories[name] = factory
+}
+
+// ProviderFactories returns the sorted names of the registered providers.
+func ProviderFactories() []string {
+	factoriesMutex.RLock()
+	defer factoriesMutex.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// RegisterConfiguredProviders creates and registers the providers enabled in
+// discovery.providers. If that section is empty, every registered provider is
+// enabled with default settings.
+func (s *Service) RegisterConfiguredProviders(deps ProviderDeps) error {
+	configs := s.config.Providers
+	if len(configs) == 0 {
+		configs = make(map[string]ProviderConfig)
+
+		for _, name := range ProviderFactories() {
+			configs[name] = ProviderConfig{}
+		}
+	}
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		config := configs[name]
+		if !config.IsEnabled() {
+			s.log.WithField("provider", name).Info("Discovery provider disabled")
+
+			continue
+		}
+
+		factoriesMutex.RLock()
+		factory, ok := factories[name]
+		factoriesMutex.RUnlock()
+
+		if !ok {
+			return fmt.Errorf("unknown discovery provider %q, available: %s", name, strings.Join(ProviderFactories(), ", "))
+		}
+
+		provider, err := factory(s.log, deps, config.Config)
+		if err != nil {
+			return fmt.Errorf("failed to create discovery provider %s: %w", name, err)
+		}
+
+		s.RegisterProviderWithOptions(provider, ProviderOptions{
+			Priority: config.Priority,
+			Timeout:  config.Timeout,
+		})
+	}
+
+	return nil
+}
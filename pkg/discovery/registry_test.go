@@ -0,0 +1,180 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingProvider blocks until its context is done.
+type blockingProvider struct{}
+
+func (p *blockingProvider) Name() string {
+	return "blocking"
+}
+
+func (p *blockingProvider) Discover(ctx context.Context, config Config) (map[string]Network, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func init() {
+	for _, name := range []string{"registry-a", "registry-b"} {
+		RegisterProviderFactory(name, func(_ *logrus.Logger, _ ProviderDeps, raw map[string]any) (Provider, error) {
+			var config struct {
+				Status string `mapstructure:"status"`
+			}
+			if err := DecodeProviderConfig(raw, &config); err != nil {
+				return nil, err
+			}
+
+			return NewMockProvider(name, map[string]Network{
+				"devnet-1": {Name: "devnet-1", Repository: name, Status: config.Status},
+			}, nil), nil
+		})
+	}
+}
+
+func TestRegisterConfiguredProviders(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name      string
+		providers map[string]ProviderConfig
+		want      []string
+		winner    string
+		err       string
+	}{
+		{
+			name:   "all registered by default",
+			want:   []string{"registry-a", "registry-b"},
+			winner: "registry-b",
+		},
+		{
+			name: "only configured",
+			providers: map[string]ProviderConfig{
+				"registry-a": {},
+			},
+			want:   []string{"registry-a"},
+			winner: "registry-a",
+		},
+		{
+			name: "disabled",
+			providers: map[string]ProviderConfig{
+				"registry-a": {},
+				"registry-b": {Enabled: &disabled},
+			},
+			want:   []string{"registry-a"},
+			winner: "registry-a",
+		},
+		{
+			name: "priority",
+			providers: map[string]ProviderConfig{
+				"registry-a": {Priority: 10},
+				"registry-b": {},
+			},
+			want:   []string{"registry-a", "registry-b"},
+			winner: "registry-a",
+		},
+		{
+			name: "unknown",
+			providers: map[string]ProviderConfig{
+				"missing": {},
+			},
+			err: `unknown discovery provider "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Only the providers registered by this test exist in this package.
+			service, err := NewService(logrus.New(), Config{Providers: tt.providers}, nil)
+			require.NoError(t, err)
+
+			err = service.RegisterConfiguredProviders(ProviderDeps{})
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			result, err := service.RunOnce(context.Background())
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(result.Providers))
+			for _, p := range result.Providers {
+				names = append(names, p.Name)
+			}
+
+			assert.Equal(t, tt.want, names)
+			assert.Equal(t, tt.winner, result.Networks["devnet-1"].Repository)
+		})
+	}
+}
+
+func TestRegisterConfiguredProviders_PassesConfig(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{Providers: map[string]ProviderConfig{
+		"registry-a": {Config: map[string]any{"status": "active"}},
+	}}, nil)
+	require.NoError(t, err)
+	require.NoError(t, service.RegisterConfiguredProviders(ProviderDeps{}))
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "active", result.Networks["devnet-1"].Status)
+}
+
+func TestRegisterConfiguredProviders_RejectsUnknownConfig(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{Providers: map[string]ProviderConfig{
+		"registry-a": {Config: map[string]any{"stauts": "active"}},
+	}}, nil)
+	require.NoError(t, err)
+
+	err = service.RegisterConfiguredProviders(ProviderDeps{})
+	require.ErrorContains(t, err, "failed to create discovery provider registry-a")
+	require.ErrorContains(t, err, "stauts")
+}
+
+func TestDecodeProviderConfig(t *testing.T) {
+	var config struct {
+		Timeout time.Duration `mapstructure:"timeout"`
+		Retries int           `mapstructure:"retries"`
+	}
+
+	// Keys are matched case-insensitively, as viper lowercases them.
+	require.NoError(t, DecodeProviderConfig(map[string]any{"timeout": "5s", "RETRIES": 3}, &config))
+	assert.Equal(t, 5*time.Second, config.Timeout)
+	assert.Equal(t, 3, config.Retries)
+
+	require.NoError(t, DecodeProviderConfig(nil, &config))
+}
+
+func TestRegisterProviderFactory_Duplicate(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterProviderFactory("registry-a", nil)
+	})
+}
+
+func TestProviderTimeout(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	service.RegisterProviderWithOptions(&blockingProvider{}, ProviderOptions{Timeout: 10 * time.Millisecond})
+	service.RegisterProvider(NewMockProvider("static", map[string]Network{"mainnet": {Name: "mainnet"}}, nil))
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.Providers, 2)
+	assert.Equal(t, ProviderStatusFailed, result.Providers[0].Status)
+	assert.Contains(t, result.Providers[0].Error, "deadline exceeded")
+	assert.Contains(t, result.Networks, "mainnet")
+}
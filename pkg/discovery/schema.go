@@ -0,0 +1,69 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethpandaops/cartographoor/pkg/jsonschema"
+)
+
+// SchemaVersion is the version of the networks.json format, set on every
+// Result. The major version is bumped on changes that can break consumers,
+// such as removing, renaming or changing the type of a field. The minor
+// version is bumped when fields are added; the schema allows unknown
+// properties, so documents of a newer minor version still validate.
+const SchemaVersion = "1.5.0"
+
+// SchemaID is the $id of the published JSON Schema.
+const SchemaID = "https://github.com/ethpandaops/cartographoor/networks.schema.json"
+
+var (
+	schemaOnce sync.Once
+	schema     *jsonschema.Schema
+)
+
+// Schema returns the JSON Schema of a Result, generated from the Go types.
+func Schema() *jsonschema.Schema {
+	schemaOnce.Do(func() {
+		schema = jsonschema.Generate(Result{})
+		schema.ID = SchemaID
+		schema.Title = "Cartographoor networks"
+		schema.Description = "Ethereum networks discovered by cartographoor, schema version " + SchemaVersion + "."
+
+		// Documents must carry a version with the same major.
+		major, _ := SchemaMajor(SchemaVersion)
+		schema.Defs["Result"].Properties["schemaVersion"].Pattern = fmt.Sprintf(`^%d\.`, major)
+	})
+
+	return schema
+}
+
+// SchemaJSON returns the indented JSON encoding of Schema.
+func SchemaJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	return data, nil
+}
+
+// ValidateResultJSON validates an encoded Result against Schema.
+func ValidateResultJSON(data []byte) error {
+	return Schema().Validate(data)
+}
+
+// SchemaMajor returns the major version of a schema version such as "1.2.0".
+func SchemaMajor(version string) (int, error) {
+	major, _, _ := strings.Cut(version, ".")
+
+	n, err := strconv.Atoi(major)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid schema version %q", version)
+	}
+
+	return n, nil
+}
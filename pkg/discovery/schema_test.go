@@ -0,0 +1,110 @@
+package discovery
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_ValidatesResult(t *testing.T) {
+	lastSuccess := time.Now()
+
+	result := Result{
+		SchemaVersion: SchemaVersion,
+		Networks: map[string]Network{
+			"devnet-1": {
+				Name:          "devnet-1",
+				Status:        "active",
+				LastUpdated:   time.Now(),
+				ChainID:       7032118028,
+				GenesisConfig: &GenesisConfig{ConsensusLayer: []ConfigFile{{Path: "config.yaml", URL: "https://example.com/config.yaml"}}},
+				ServiceURLs:   &ServiceURLs{Dora: "https://dora.example.com"},
+				Forks:         &ForksConfig{Consensus: map[string]ConsensusForkConfig{"fulu": {Epoch: 10}}},
+				BlobSchedule:  []BlobSchedule{{Epoch: 10, MaxBlobsPerBlock: 12}},
+				ConsensusSpec: &ConsensusSpec{
+					PresetBase:   "mainnet",
+					ForkVersions: map[string]string{"fulu": "0x70000038"},
+					Extra:        map[string]any{"MAX_PAYLOAD_SIZE": 10485760, "BOOTNODES": []any{"enr:-abc"}},
+				},
+				ExecutionSpec: &ExecutionSpec{GasLimit: 60000000, TerminalTotalDifficulty: "0"},
+				Stale:         &StaleInfo{Since: time.Now(), Age: 1.5},
+			},
+		},
+		NetworkMetadata: map[string]RepositoryMetadata{
+			"ethpandaops/devnets": {DisplayName: "Devnets"},
+		},
+		Clients:    map[string]ClientInfo{"geth": {Name: "geth", Type: "execution"}},
+		LastUpdate: time.Now(),
+		Providers:  []ProviderInfo{{Name: "github", Status: ProviderStatusSuccess, LastSuccess: &lastSuccess}},
+	}
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	assert.NoError(t, ValidateResultJSON(data))
+}
+
+func TestSchema_RejectsShapeChanges(t *testing.T) {
+	valid := `"schemaVersion": "1.0.0", "networkMetadata": {}, "clients": {}, "lastUpdate": "2025-01-01T00:00:00Z", "duration": 1, "providers": [], "partial": false`
+
+	tests := []struct {
+		name  string
+		doc   string
+		error string
+	}{
+		{
+			name:  "serviceUrls as a list",
+			doc:   `{` + valid + `, "networks": {"devnet-1": {"name": "devnet-1", "status": "active", "lastUpdated": "2025-01-01T00:00:00Z", "selfHostedDns": false, "serviceUrls": ["https://dora.example.com"]}}}`,
+			error: "$.networks.devnet-1.serviceUrls: expected object, got array",
+		},
+		{
+			name:  "blobSchedule entry missing maxBlobsPerBlock",
+			doc:   `{` + valid + `, "networks": {"devnet-1": {"name": "devnet-1", "status": "active", "lastUpdated": "2025-01-01T00:00:00Z", "selfHostedDns": false, "blobSchedule": [{"epoch": 1}]}}}`,
+			error: `$.networks.devnet-1.blobSchedule[0]: missing required property "maxBlobsPerBlock"`,
+		},
+		{
+			name:  "incompatible major version",
+			doc:   strings.Replace(`{`+valid+`, "networks": {}}`, `"1.0.0"`, `"2.0.0"`, 1),
+			error: `$.schemaVersion: "2.0.0" does not match`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, ValidateResultJSON([]byte(tt.doc)), tt.error)
+		})
+	}
+}
+
+func TestSchema_AllowsAddedFields(t *testing.T) {
+	// Fields added by a newer minor version don't break validation.
+	doc := `{"schemaVersion": "1.99.0", "networkMetadata": {}, "clients": {}, "lastUpdate": "2025-01-01T00:00:00Z", "duration": 1, "providers": [], "partial": false, "newField": 1,
+		"networks": {"devnet-1": {"name": "devnet-1", "status": "active", "lastUpdated": "2025-01-01T00:00:00Z", "selfHostedDns": false, "newNetworkField": {"a": 1}}}}`
+
+	assert.NoError(t, ValidateResultJSON([]byte(doc)))
+}
+
+func TestSchemaJSON(t *testing.T) {
+	data, err := SchemaJSON()
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, SchemaID, decoded["$id"])
+	assert.Equal(t, "#/$defs/Result", decoded["$ref"])
+	assert.Contains(t, decoded["$defs"], "Network")
+}
+
+func TestSchemaMajor(t *testing.T) {
+	major, err := SchemaMajor("1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, 1, major)
+
+	_, err = SchemaMajor("v1")
+	assert.Error(t, err)
+}
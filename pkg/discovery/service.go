@@ -1,13 +1,18 @@
 package discovery
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
 )
 
 // ClientDiscovererInterface defines the interface for client discovery.
@@ -19,13 +24,23 @@ type ClientDiscovererInterface interface {
 type Service struct {
 	log              *logrus.Logger
 	config           Config
-	providers        []Provider
+	providers        []registeredProvider
 	resultChan       chan Result
 	resultFuncs      []ResultHandler
 	ticker           *time.Ticker
 	wg               sync.WaitGroup
 	mutex            sync.Mutex
 	clientDiscoverer ClientDiscovererInterface
+	rateLimits       *ratelimit.Tracker
+	lastSuccess      map[string]time.Time
+	lastStatus       map[string]string
+	lastGood         map[string]map[string]goodNetwork
+	versions         map[string]networkVersion
+	listings         map[string][]SourceRepository
+	triggerChan      chan struct{}
+	triggerMutex     sync.Mutex
+	triggerTimer     *time.Timer
+	pending          *pendingTrigger
 }
 
 // NewService creates a new discovery service. The clientDiscoverer is injected
@@ -35,27 +50,65 @@ func NewService(log *logrus.Logger, cfg Config, clientDiscoverer ClientDiscovere
 	log = log.WithField("module", "discovery").Logger
 
 	// Set default values if not specified
-	if cfg.Interval == 0 {
-		cfg.Interval = 1 * time.Hour
+	cfg.SetDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid discovery config: %w", err)
 	}
 
 	return &Service{
 		log:              log,
 		config:           cfg,
-		providers:        []Provider{},
+		providers:        []registeredProvider{},
 		resultChan:       make(chan Result, 10),
 		resultFuncs:      []ResultHandler{},
 		clientDiscoverer: clientDiscoverer,
+		lastSuccess:      make(map[string]time.Time),
+		lastStatus:       make(map[string]string),
+		lastGood:         make(map[string]map[string]goodNetwork),
+		versions:         make(map[string]networkVersion),
+		listings:         make(map[string][]SourceRepository),
+		triggerChan:      make(chan struct{}, 1),
 	}, nil
 }
 
+// Interval returns the discovery interval, after defaults are applied.
+func (s *Service) Interval() time.Duration {
+	return s.currentConfig().Interval
+}
+
+// ProviderOptions configures how a registered provider runs.
+type ProviderOptions struct {
+	// Priority decides which provider wins when several discover a network with
+	// the same name. Higher priorities win, ties go to the provider registered
+	// last.
+	Priority int
+	// Timeout bounds a single Discover call, zero means no timeout.
+	Timeout time.Duration
+}
+
+// registeredProvider is a provider and the options it was registered with.
+type registeredProvider struct {
+	Provider
+	ProviderOptions
+}
+
 // RegisterProvider registers a provider with the discovery service.
 func (s *Service) RegisterProvider(provider Provider) {
+	s.RegisterProviderWithOptions(provider, ProviderOptions{})
+}
+
+// RegisterProviderWithOptions registers a provider with the discovery service.
+func (s *Service) RegisterProviderWithOptions(provider Provider, opts ProviderOptions) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.providers = append(s.providers, provider)
-	s.log.WithField("provider", provider.Name()).Info("Registered discovery provider")
+	s.providers = append(s.providers, registeredProvider{Provider: provider, ProviderOptions: opts})
+	s.log.WithFields(logrus.Fields{
+		"provider": provider.Name(),
+		"priority": opts.Priority,
+		"timeout":  opts.Timeout,
+	}).Info("Registered discovery provider")
 }
 
 // OnResult registers a function to be called when a discovery result is available.
@@ -66,9 +119,18 @@ func (s *Service) OnResult(fn ResultHandler) {
 	s.resultFuncs = append(s.resultFuncs, fn)
 }
 
+// ReportRateLimits reports the GitHub API rate limits tracked by rateLimits in
+// every result.
+func (s *Service) ReportRateLimits(rateLimits *ratelimit.Tracker) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rateLimits = rateLimits
+}
+
 // Start starts the discovery service.
 func (s *Service) Start(ctx context.Context) error {
-	s.log.WithField("interval", s.config.Interval).Info("Starting discovery service")
+	s.log.WithField("interval", s.Interval()).Info("Starting discovery service")
 
 	// Start the result processor
 
@@ -78,23 +140,27 @@ func (s *Service) Start(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case result := <-s.resultChan:
+				// Handlers run without the lock so they can call back into
+				// the service, e.g. to read its config or trigger a run.
 				s.mutex.Lock()
+				handlers := slices.Clone(s.resultFuncs)
+				s.mutex.Unlock()
 
-				for _, fn := range s.resultFuncs {
+				for _, fn := range handlers {
 					fn(result)
 				}
-
-				s.mutex.Unlock()
 			}
 		}
 	})
 
 	// Start the ticker
+	s.mutex.Lock()
 	s.ticker = time.NewTicker(s.config.Interval)
+	s.mutex.Unlock()
 
 	s.wg.Go(func() {
 		// Run an initial discovery
-		if err := s.runDiscovery(ctx); err != nil {
+		if err := s.runDiscovery(ctx, nil); err != nil {
 			s.log.WithError(err).Error("Failed to run initial discovery")
 		}
 
@@ -103,9 +169,26 @@ func (s *Service) Start(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case <-s.ticker.C:
-				if err := s.runDiscovery(ctx); err != nil {
+				// A full run covers anything triggered so far.
+				s.clearTriggers()
+
+				if err := s.runDiscovery(ctx, nil); err != nil {
 					s.log.WithError(err).Error("Failed to run discovery")
 				}
+			case <-s.triggerChan:
+				scope, reasons, ok := s.takeTrigger()
+				if !ok {
+					continue
+				}
+
+				s.log.WithFields(logrus.Fields{
+					"reasons":      reasons,
+					"repositories": scope,
+				}).Info("Running triggered discovery")
+
+				if err := s.runDiscovery(ctx, scope); err != nil {
+					s.log.WithError(err).Error("Failed to run triggered discovery")
+				}
 			}
 		}
 	})
@@ -121,6 +204,12 @@ func (s *Service) Stop(ctx context.Context) error {
 		s.ticker.Stop()
 	}
 
+	s.triggerMutex.Lock()
+	if s.triggerTimer != nil {
+		s.triggerTimer.Stop()
+	}
+	s.triggerMutex.Unlock()
+
 	done := make(chan struct{})
 
 	go func() {
@@ -136,9 +225,18 @@ func (s *Service) Stop(ctx context.Context) error {
 	}
 }
 
+// providerStatus returns the status of a provider's last run, or an empty
+// string if it hasn't run yet.
+func (s *Service) providerStatus(name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.lastStatus[name]
+}
+
 // RunOnce executes a single discovery run and returns the result directly.
 func (s *Service) RunOnce(ctx context.Context) (Result, error) {
-	result, err := s.executeDiscovery(ctx)
+	result, err := s.executeDiscovery(ctx, nil)
 	if err != nil {
 		return Result{
 			Networks:        make(map[string]Network),
@@ -150,9 +248,10 @@ func (s *Service) RunOnce(ctx context.Context) (Result, error) {
 	return result, nil
 }
 
-// runDiscovery runs the discovery process and sends the result to the result channel.
-func (s *Service) runDiscovery(ctx context.Context) error {
-	result, err := s.executeDiscovery(ctx)
+// runDiscovery runs the discovery process and sends the result to the result
+// channel. A non-nil scope limits scoped providers to those repositories.
+func (s *Service) runDiscovery(ctx context.Context, scope []string) error {
+	result, err := s.executeDiscovery(ctx, scope)
 	if err != nil {
 		return err
 	}
@@ -167,14 +266,18 @@ func (s *Service) runDiscovery(ctx context.Context) error {
 	return nil
 }
 
-// executeDiscovery performs the actual discovery process and returns the result.
-func (s *Service) executeDiscovery(ctx context.Context) (Result, error) {
+// executeDiscovery performs the actual discovery process and returns the
+// result. A non-nil scope limits scoped providers to those repositories, the
+// networks of their other repositories are carried over from the last run.
+func (s *Service) executeDiscovery(ctx context.Context, scope []string) (Result, error) {
 	start := time.Now()
 
 	s.log.Info("Running discovery")
 
+	// Use the same config for the whole run, even if it is updated meanwhile.
 	s.mutex.Lock()
 	providers := s.providers
+	config := s.config
 	s.mutex.Unlock()
 
 	if len(providers) == 0 {
@@ -187,9 +290,15 @@ func (s *Service) executeDiscovery(ctx context.Context) (Result, error) {
 		}, nil
 	}
 
+	config.GitHub.Repositories = s.expandSources(ctx, config, providers, scope == nil)
+
 	type providerResult struct {
+		index    int
 		networks map[string]Network
-		provider Provider
+		carried  map[string]goodNetwork
+		provider registeredProvider
+		duration time.Duration
+		warnings []Warning
 		err      error
 	}
 
@@ -197,38 +306,75 @@ func (s *Service) executeDiscovery(ctx context.Context) (Result, error) {
 	resultCh := make(chan providerResult, len(providers))
 
 	// Run discovery for each provider
-	for _, provider := range providers {
-		go func(p Provider) {
+	for i, provider := range providers {
+		go func(idx int, p registeredProvider) {
 			pLog := s.log.WithField("provider", p.Name())
 			pLog.Info("Running discovery provider")
 
-			networkMap, err := p.Discover(ctx, s.config)
+			providerStart := time.Now()
+
+			providerCtx := ctx
+			if p.Timeout > 0 {
+				var cancel context.CancelFunc
+
+				providerCtx, cancel = context.WithTimeout(ctx, p.Timeout)
+				defer cancel()
+			}
+
+			var (
+				networkMap map[string]Network
+				carried    map[string]goodNetwork
+				warnings   []Warning
+				err        error
+			)
+
+			// Only providers that fully succeeded last time can carry over
+			// networks, otherwise they rescan everything.
+			if scoped, ok := p.Provider.(ScopedProvider); ok && scope != nil && s.providerStatus(p.Name()) == ProviderStatusSuccess {
+				networkMap, carried, warnings, err = s.discoverScoped(providerCtx, config, scoped, scope)
+			} else {
+				networkMap, warnings, err = discover(providerCtx, config, p.Provider, nil)
+			}
+
 			if err != nil {
 				pLog.WithError(err).Error("Failed to discover networks")
 
+				// Networks are still passed on, as providers may return the
+				// networks they did discover alongside RepositoryErrors.
 				resultCh <- providerResult{
-					networks: nil,
+					index:    idx,
+					networks: networkMap,
+					carried:  carried,
 					provider: p,
+					duration: time.Since(providerStart),
+					warnings: warnings,
 					err:      err,
 				}
 
 				return
 			}
 
-			pLog.WithField("networks", len(networkMap)).Info("Discovery complete")
+			pLog.WithFields(logrus.Fields{
+				"networks": len(networkMap),
+				"carried":  len(carried),
+			}).Info("Discovery complete")
 
 			resultCh <- providerResult{
+				index:    idx,
 				networks: networkMap,
+				carried:  carried,
 				provider: p,
+				duration: time.Since(providerStart),
+				warnings: warnings,
 				err:      nil,
 			}
-		}(provider)
+		}(i, provider)
 	}
 
 	// Collect results
 	var (
 		allNetworks = make(map[string]Network)
-		provInfos   = make([]ProviderInfo, 0)
+		provResults = make([]providerResult, len(providers))
 	)
 
 	// Wait for all provider goroutines to complete
@@ -241,17 +387,92 @@ func (s *Service) executeDiscovery(ctx context.Context) (Result, error) {
 				Clients:         make(map[string]ClientInfo),
 			}, ctx.Err()
 		case pr := <-resultCh:
-			if pr.err == nil && pr.networks != nil {
-				// Merge networks, newer ones will overwrite older ones with the same key
-				maps.Copy(allNetworks, pr.networks)
+			provResults[pr.index] = pr
+		}
+	}
 
-				provInfos = append(provInfos, ProviderInfo{Name: pr.provider.Name()})
-			}
+	// Build provider infos in registration order, so the output does not
+	// depend on which provider happened to finish first.
+	var (
+		provInfos     = make([]ProviderInfo, 0, len(provResults))
+		freshByIndex  = make([]map[string]Network, len(provResults))
+		staleByIndex  = make([]map[string]Network, len(provResults))
+		staleNetworks = make(map[string]Network)
+		warnings      []Warning
+		partial       = false
+		now           = time.Now()
+	)
+
+	for i, pr := range provResults {
+		fresh, stale, info := s.resolveProviderResult(pr.provider.Name(), pr.networks, pr.carried, pr.duration, pr.err, now)
+		if info.Failed() {
+			partial = true
+		}
+
+		freshByIndex[i], staleByIndex[i] = fresh, stale
+		warnings = append(warnings, pr.warnings...)
+
+		provInfos = append(provInfos, info)
+	}
+
+	// Merge networks in ascending priority, so higher priority providers
+	// overwrite networks with the same name. The sort is stable, so on a tie
+	// the provider registered last wins.
+	mergeOrder := make([]int, len(provResults))
+	for i := range mergeOrder {
+		mergeOrder[i] = i
+	}
+
+	slices.SortStableFunc(mergeOrder, func(a, b int) int {
+		return cmp.Compare(provResults[a].provider.Priority, provResults[b].provider.Priority)
+	})
+
+	candidates := make(map[string][]mergeCandidate)
+
+	for _, i := range mergeOrder {
+		for name, network := range freshByIndex[i] {
+			candidates[name] = append(candidates[name], mergeCandidate{
+				provider: provResults[i].provider.Name(),
+				network:  network,
+			})
+		}
+
+		maps.Copy(staleNetworks, staleByIndex[i])
+	}
+
+	conflicts := make([]MergeConflict, 0)
+
+	for name, networkCandidates := range candidates {
+		network, conflict := mergeNetwork(name, networkCandidates, config.Merge)
+		allNetworks[name] = network
+
+		if conflict != nil {
+			s.log.WithFields(logrus.Fields{
+				"network":   name,
+				"providers": conflict.Providers,
+				"winner":    conflict.Winner,
+				"differing": conflict.Differing,
+			}).Debug("Network discovered by multiple providers")
+
+			conflicts = append(conflicts, *conflict)
 		}
 	}
 
+	slices.SortFunc(conflicts, func(a, b MergeConflict) int {
+		return cmp.Compare(a.Network, b.Network)
+	})
+
+	// Stale networks never override freshly discovered ones.
+	for name, network := range staleNetworks {
+		if _, exists := allNetworks[name]; !exists {
+			allNetworks[name] = network
+		}
+	}
+
+	s.stampNetworks(allNetworks, now)
+
 	// Build repository metadata from config
-	networkMetadata := buildNetworkMetadata(s.config, allNetworks)
+	networkMetadata := buildNetworkMetadata(config, allNetworks)
 
 	// Discover client information (skipped if no discoverer was injected)
 	clientInfo := make(map[string]ClientInfo)
@@ -265,27 +486,117 @@ func (s *Service) executeDiscovery(ctx context.Context) (Result, error) {
 		}
 	}
 
+	rateLimits := s.reportRateLimits()
+
+	sortWarnings(warnings)
+
 	// Create result
 	duration := time.Since(start).Seconds()
 	result := Result{
+		SchemaVersion:   SchemaVersion,
 		Networks:        allNetworks,
 		NetworkMetadata: networkMetadata,
 		Clients:         clientInfo,
 		LastUpdate:      time.Now(),
 		Duration:        duration,
 		Providers:       provInfos,
+		Partial:         partial,
+		Conflicts:       conflicts,
+		RateLimits:      rateLimits,
+		Warnings:        warnings,
 	}
 
+	observeResult(result)
+
 	s.log.WithFields(logrus.Fields{
 		"networks":         len(allNetworks),
 		"network_metadata": len(networkMetadata),
 		"clients":          len(clientInfo),
 		"duration":         duration,
+		"partial":          partial,
+		"warnings":         len(warnings),
 	}).Info("Discovery complete")
 
 	return result, nil
 }
 
+// expandSources returns the configured repositories and those of the
+// configured sources. Full runs list the repositories of the sources again,
+// scoped runs and orgs that fail to list reuse the last listing.
+func (s *Service) expandSources(ctx context.Context, config Config, providers []registeredProvider, relist bool) []GitHubRepositoryConfig {
+	if len(config.GitHub.Sources) == 0 {
+		return config.GitHub.Repositories
+	}
+
+	if relist {
+		for _, p := range providers {
+			lister, ok := p.Provider.(RepositoryLister)
+			if !ok {
+				continue
+			}
+
+			listed, err := ListSourceRepositories(ctx, config, lister)
+			if err != nil {
+				s.log.WithError(err).Warn("Failed to list source repositories, reusing the last listing")
+			}
+
+			s.mutex.Lock()
+			maps.Copy(s.listings, listed)
+			s.mutex.Unlock()
+
+			break
+		}
+	}
+
+	repositories := ExpandSources(config, s.listedRepositories())
+
+	s.log.WithFields(logrus.Fields{
+		"configured": len(config.GitHub.Repositories),
+		"total":      len(repositories),
+	}).Debug("Expanded GitHub repository sources")
+
+	return repositories
+}
+
+// listedRepositories returns the last listing of the source orgs.
+func (s *Service) listedRepositories() map[string][]SourceRepository {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return maps.Clone(s.listings)
+}
+
+// reportRateLimits returns the tracked GitHub API rate limits and logs them.
+func (s *Service) reportRateLimits() []RateLimit {
+	s.mutex.Lock()
+	tracker := s.rateLimits
+	s.mutex.Unlock()
+
+	limits := tracker.Limits()
+	if len(limits) == 0 {
+		return nil
+	}
+
+	rateLimits := make([]RateLimit, 0, len(limits))
+
+	for _, limit := range limits {
+		s.log.WithFields(logrus.Fields{
+			"resource":  limit.Resource,
+			"remaining": limit.Remaining,
+			"limit":     limit.Limit,
+			"reset":     limit.Reset,
+		}).Info("GitHub API rate limit")
+
+		rateLimits = append(rateLimits, RateLimit(limit))
+	}
+
+	if tracker.Low() {
+		s.log.Warn("GitHub API rate limit is low, skipping low-priority work until it resets")
+	}
+
+	return rateLimits
+}
+
 // buildNetworkMetadata builds the network metadata from GitHub repository configurations.
 func buildNetworkMetadata(config Config, networks map[string]Network) map[string]RepositoryMetadata {
 	metadata := make(map[string]RepositoryMetadata)
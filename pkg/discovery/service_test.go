@@ -2,12 +2,16 @@ package discovery
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
 )
 
 // Ensure MockClientDiscoverer implements ClientDiscovererInterface.
@@ -55,13 +59,9 @@ func (p *MockProvider) Name() string {
 	return p.name
 }
 
-// Discover returns the mock networks or error.
+// Discover returns the mock networks and error.
 func (p *MockProvider) Discover(ctx context.Context, config Config) (map[string]Network, error) {
-	if p.err != nil {
-		return nil, p.err
-	}
-
-	return p.networks, nil
+	return p.networks, p.err
 }
 
 func TestDiscoveryService(t *testing.T) {
@@ -175,3 +175,171 @@ func TestDiscoveryService_NoProviders(t *testing.T) {
 	// Just cancel the main context instead
 	cancel()
 }
+
+func TestDiscoveryService_HandlerCallsService(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.DebugLevel)
+
+	service, err := NewService(log, Config{Interval: time.Hour}, nil)
+	require.NoError(t, err)
+
+	service.clientDiscoverer = NewMockClientDiscoverer(log)
+
+	// Handlers run without the service lock, so they can call back into it.
+	intervals := make(chan time.Duration, 1)
+	service.OnResult(func(Result) {
+		intervals <- service.Interval()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	require.NoError(t, service.Start(ctx))
+
+	select {
+	case interval := <-intervals:
+		assert.Equal(t, time.Hour, interval)
+	case <-time.After(1500 * time.Millisecond):
+		t.Fatal("Timeout waiting for result handler")
+	}
+
+	cancel()
+}
+
+func TestDiscoveryService_ProviderFailure(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.DebugLevel)
+
+	service, err := NewService(log, Config{}, nil)
+	require.NoError(t, err)
+
+	networks := map[string]Network{
+		"mainnet": {Name: "mainnet", Status: "active"},
+	}
+
+	service.RegisterProvider(NewMockProvider("failing", nil, fmt.Errorf("github unavailable")))
+	service.RegisterProvider(NewMockProvider("static", networks, nil))
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, result.Partial)
+	assert.Len(t, result.Networks, 1)
+	require.Len(t, result.Providers, 2)
+
+	// Providers are reported in registration order.
+	failing := result.Providers[0]
+	assert.Equal(t, "failing", failing.Name)
+	assert.Equal(t, ProviderStatusFailed, failing.Status)
+	assert.Equal(t, "github unavailable", failing.Error)
+	assert.Equal(t, 0, failing.Networks)
+	assert.Nil(t, failing.LastSuccess)
+
+	static := result.Providers[1]
+	assert.Equal(t, "static", static.Name)
+	assert.Equal(t, ProviderStatusSuccess, static.Status)
+	assert.Empty(t, static.Error)
+	assert.Equal(t, 1, static.Networks)
+	require.NotNil(t, static.LastSuccess)
+
+	failed := result.FailedProviders()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "failing", failed[0].Name)
+}
+
+func TestDiscoveryService_LastSuccessRetainedOnFailure(t *testing.T) {
+	log := logrus.New()
+
+	service, err := NewService(log, Config{}, nil)
+	require.NoError(t, err)
+
+	provider := NewMockProvider("mock", map[string]Network{"devnet-1": {Name: "devnet-1"}}, nil)
+	service.RegisterProvider(provider)
+
+	first, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, first.Providers[0].LastSuccess)
+	assert.False(t, first.Partial)
+
+	provider.err = fmt.Errorf("boom")
+
+	second, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, second.Partial)
+	assert.Equal(t, ProviderStatusFailed, second.Providers[0].Status)
+	require.NotNil(t, second.Providers[0].LastSuccess)
+	assert.Equal(t, *first.Providers[0].LastSuccess, *second.Providers[0].LastSuccess)
+}
+
+func TestDiscoveryService_ReportsRateLimits(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	service.RegisterProvider(NewMockProvider("static", map[string]Network{}, nil))
+
+	// Nothing is reported before any GitHub API call.
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.RateLimits)
+
+	rateLimits, err := ratelimit.New(ratelimit.Config{})
+	require.NoError(t, err)
+
+	rateLimits.Observe(http.Header{
+		"X-Ratelimit-Resource":  {"core"},
+		"X-Ratelimit-Limit":     {"5000"},
+		"X-Ratelimit-Remaining": {"4321"},
+		"X-Ratelimit-Reset":     {"1700000000"},
+	})
+	service.ReportRateLimits(rateLimits)
+
+	result, err = service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []RateLimit{{
+		Resource:  "core",
+		Limit:     5000,
+		Remaining: 4321,
+		Reset:     time.Unix(1700000000, 0).UTC(),
+	}}, result.RateLimits)
+}
+
+// warningProvider is a mock provider that reports a warning.
+type warningProvider struct {
+	*MockProvider
+	warning Warning
+}
+
+// DiscoverWithWarnings returns the mock networks and the warning.
+func (p *warningProvider) DiscoverWithWarnings(ctx context.Context, config Config, _ []string) (map[string]Network, []Warning, error) {
+	networks, err := p.MockProvider.Discover(ctx, config)
+
+	return networks, []Warning{p.warning}, err
+}
+
+func TestDiscoveryService_ReportsWarnings(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	service.RegisterProvider(&warningProvider{
+		MockProvider: NewMockProvider("github", map[string]Network{}, nil),
+		warning: Warning{
+			Repository: "ethpandaops/fusaka-devnets",
+			Network:    "fusaka-devnet-1",
+			File:       "kubernetes/devnet-1/values.yaml",
+			Message:    "no domain",
+		},
+	})
+	service.RegisterProvider(NewMockProvider("static", map[string]Network{}, nil))
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []Warning{{
+		Provider:   "github",
+		Repository: "ethpandaops/fusaka-devnets",
+		Network:    "fusaka-devnet-1",
+		File:       "kubernetes/devnet-1/values.yaml",
+		Message:    "no domain",
+	}}, result.Warnings)
+}
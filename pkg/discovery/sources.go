@@ -0,0 +1,216 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// GitHubSourceConfig represents a source of GitHub repositories that is
+// expanded at discovery time: the repositories of an org whose name matches
+// Pattern and that have Topic, if set.
+type GitHubSourceConfig struct {
+	// Org is the organization or user to list repositories of.
+	Org string `mapstructure:"org"`
+	// Pattern is a glob that repository names must match, e.g. "*-devnets".
+	Pattern string `mapstructure:"pattern"`
+	// Topic is a topic that repositories must have.
+	Topic string `mapstructure:"topic"`
+	// IncludeArchived also discovers archived repositories.
+	IncludeArchived bool `mapstructure:"includeArchived"`
+	// Ref and Paths apply to all repositories of the source.
+	Ref   string          `mapstructure:"ref"`
+	Paths RepositoryPaths `mapstructure:"paths"`
+}
+
+// Validate validates the source.
+func (c *GitHubSourceConfig) Validate() error {
+	if c.Org == "" || strings.Contains(c.Org, "/") {
+		return fmt.Errorf("org must be an organization name, got %q", c.Org)
+	}
+
+	if c.Pattern == "" && c.Topic == "" {
+		return errors.New("pattern or topic is required")
+	}
+
+	if _, err := path.Match(c.Pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", c.Pattern, err)
+	}
+
+	return c.Paths.Validate()
+}
+
+// Matches returns true if repo belongs to the source.
+func (c *GitHubSourceConfig) Matches(repo SourceRepository) bool {
+	owner, name, _ := strings.Cut(repo.Name, "/")
+	if !strings.EqualFold(owner, c.Org) || repo.Fork || (repo.Archived && !c.IncludeArchived) {
+		return false
+	}
+
+	if c.Pattern != "" {
+		if ok, _ := path.Match(strings.ToLower(c.Pattern), strings.ToLower(name)); !ok {
+			return false
+		}
+	}
+
+	return c.Topic == "" || slices.Contains(repo.Topics, strings.ToLower(c.Topic))
+}
+
+// SourceRepository is a repository listed for a GitHubSourceConfig.
+type SourceRepository struct {
+	// Name is the repository in owner/repo form.
+	Name        string
+	Description string
+	Homepage    string
+	URL         string
+	Topics      []string
+	Archived    bool
+	Fork        bool
+}
+
+// RepositoryLister is implemented by providers that can list the repositories
+// of a GitHub organization, which expands config.GitHub.Sources.
+type RepositoryLister interface {
+	// ListRepositories lists the repositories of org.
+	ListRepositories(ctx context.Context, config Config, org string) ([]SourceRepository, error)
+}
+
+// ListSourceRepositories lists the repositories of every org of
+// config.GitHub.Sources, by lowercase org. Orgs that fail to list are left out
+// and their errors joined.
+func ListSourceRepositories(ctx context.Context, config Config, lister RepositoryLister) (map[string][]SourceRepository, error) {
+	var (
+		listed = make(map[string][]SourceRepository)
+		errs   []error
+	)
+
+	for _, source := range config.GitHub.Sources {
+		org := strings.ToLower(source.Org)
+		if _, ok := listed[org]; ok {
+			continue
+		}
+
+		repos, err := lister.ListRepositories(ctx, config, source.Org)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list repositories of %s: %w", source.Org, err))
+
+			continue
+		}
+
+		listed[org] = repos
+	}
+
+	return listed, errors.Join(errs...)
+}
+
+// ExpandSources returns config.GitHub.Repositories followed by the listed
+// repositories that match a source, sorted by name. Listed repositories get
+// defaults derived from their metadata; if they are also configured
+// explicitly, the fields set on the explicit entry take precedence. A
+// repository matched by several sources belongs to the first.
+func ExpandSources(config Config, listed map[string][]SourceRepository) []GitHubRepositoryConfig {
+	repositories := slices.Clone(config.GitHub.Repositories)
+
+	explicit := make(map[string]int, len(repositories))
+	for i, repo := range repositories {
+		explicit[strings.ToLower(repo.Name)] = i
+	}
+
+	var (
+		expanded []GitHubRepositoryConfig
+		seen     = make(map[string]bool)
+	)
+
+	for _, source := range config.GitHub.Sources {
+		for _, repo := range listed[strings.ToLower(source.Org)] {
+			key := strings.ToLower(repo.Name)
+			if seen[key] || !source.Matches(repo) {
+				continue
+			}
+
+			seen[key] = true
+			derived := deriveRepositoryConfig(source, repo)
+
+			if i, ok := explicit[key]; ok {
+				repositories[i] = overrideRepositoryConfig(derived, repositories[i])
+
+				continue
+			}
+
+			expanded = append(expanded, derived)
+		}
+	}
+
+	slices.SortFunc(expanded, func(a, b GitHubRepositoryConfig) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	return append(repositories, expanded...)
+}
+
+// deriveRepositoryConfig derives the config of a repository of source from its
+// metadata. "fusaka-devnets" gets the name prefix "fusaka-" and the display
+// name "Fusaka Devnets".
+func deriveRepositoryConfig(source GitHubSourceConfig, repo SourceRepository) GitHubRepositoryConfig {
+	_, name, _ := strings.Cut(repo.Name, "/")
+	name = strings.ToLower(name)
+
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	var links []Link
+	if repo.Homepage != "" {
+		links = append(links, Link{Title: "Homepage", URL: repo.Homepage})
+	}
+
+	if repo.URL != "" {
+		links = append(links, Link{Title: "GitHub", URL: repo.URL})
+	}
+
+	return GitHubRepositoryConfig{
+		Name:        repo.Name,
+		NamePrefix:  strings.TrimSuffix(name, "-devnets") + "-",
+		DisplayName: strings.Join(words, " "),
+		Description: repo.Description,
+		Links:       links,
+		Ref:         source.Ref,
+		Paths:       source.Paths,
+	}
+}
+
+// overrideRepositoryConfig returns derived with the fields set on explicit.
+func overrideRepositoryConfig(derived, explicit GitHubRepositoryConfig) GitHubRepositoryConfig {
+	result := explicit
+
+	for _, field := range []struct {
+		value *string
+		def   string
+	}{
+		{&result.NamePrefix, derived.NamePrefix},
+		{&result.DisplayName, derived.DisplayName},
+		{&result.Description, derived.Description},
+		{&result.Image, derived.Image},
+		{&result.Ref, derived.Ref},
+		{&result.Paths.NetworkConfigs, derived.Paths.NetworkConfigs},
+		{&result.Paths.Active, derived.Paths.Active},
+		{&result.Paths.Archived, derived.Paths.Archived},
+		{&result.Paths.ValuesFile, derived.Paths.ValuesFile},
+		{&result.Paths.ImagesFile, derived.Paths.ImagesFile},
+		{&result.Paths.Inventories, derived.Paths.Inventories},
+	} {
+		if *field.value == "" {
+			*field.value = field.def
+		}
+	}
+
+	if len(result.Links) == 0 {
+		result.Links = derived.Links
+	}
+
+	return result
+}
@@ -0,0 +1,175 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testListing() map[string][]SourceRepository {
+	return map[string][]SourceRepository{
+		"ethpandaops": {
+			{
+				Name:        "ethpandaops/fusaka-devnets",
+				Description: "Fusaka devnets",
+				Homepage:    "https://fusaka.ethpandaops.io",
+				URL:         "https://github.com/ethpandaops/fusaka-devnets",
+				Topics:      []string{"devnet"},
+			},
+			{Name: "ethpandaops/pectra-devnets", URL: "https://github.com/ethpandaops/pectra-devnets", Archived: true},
+			{Name: "ethpandaops/glamsterdam-devnets", URL: "https://github.com/ethpandaops/glamsterdam-devnets"},
+			{Name: "ethpandaops/fork-devnets", Fork: true},
+			{Name: "ethpandaops/ethereum-helm-charts", Topics: []string{"devnet"}},
+		},
+	}
+}
+
+func TestExpandSources(t *testing.T) {
+	var config Config
+	config.GitHub.Sources = []GitHubSourceConfig{{Org: "ethpandaops", Pattern: "*-devnets", Ref: "main"}}
+	config.GitHub.Repositories = []GitHubRepositoryConfig{
+		{Name: "ethpandaops/mainnet"},
+		{Name: "ethpandaops/glamsterdam-devnets", NamePrefix: "gl-", Paths: RepositoryPaths{NetworkConfigs: "configs"}},
+	}
+
+	repos := ExpandSources(config, testListing())
+
+	// Explicit entries keep their position and override the derived fields,
+	// archived repositories and forks are left out.
+	assert.Equal(t, []GitHubRepositoryConfig{
+		{Name: "ethpandaops/mainnet"},
+		{
+			Name:        "ethpandaops/glamsterdam-devnets",
+			NamePrefix:  "gl-",
+			DisplayName: "Glamsterdam Devnets",
+			Links:       []Link{{Title: "GitHub", URL: "https://github.com/ethpandaops/glamsterdam-devnets"}},
+			Ref:         "main",
+			Paths:       RepositoryPaths{NetworkConfigs: "configs"},
+		},
+		{
+			Name:        "ethpandaops/fusaka-devnets",
+			NamePrefix:  "fusaka-",
+			DisplayName: "Fusaka Devnets",
+			Description: "Fusaka devnets",
+			Links: []Link{
+				{Title: "Homepage", URL: "https://fusaka.ethpandaops.io"},
+				{Title: "GitHub", URL: "https://github.com/ethpandaops/fusaka-devnets"},
+			},
+			Ref: "main",
+		},
+	}, repos)
+}
+
+func TestExpandSources_Topic(t *testing.T) {
+	var config Config
+	config.GitHub.Sources = []GitHubSourceConfig{{Org: "EthPandaOps", Topic: "Devnet", IncludeArchived: true}}
+
+	names := make([]string, 0)
+	for _, repo := range ExpandSources(config, testListing()) {
+		names = append(names, repo.Name)
+	}
+
+	assert.Equal(t, []string{"ethpandaops/ethereum-helm-charts", "ethpandaops/fusaka-devnets"}, names)
+}
+
+func TestGitHubSourceConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  GitHubSourceConfig
+		wantErr string
+	}{
+		{name: "pattern", source: GitHubSourceConfig{Org: "ethpandaops", Pattern: "*-devnets"}},
+		{name: "topic", source: GitHubSourceConfig{Org: "ethpandaops", Topic: "devnet"}},
+		{name: "no org", source: GitHubSourceConfig{Pattern: "*"}, wantErr: "org must be an organization name"},
+		{name: "org with repo", source: GitHubSourceConfig{Org: "ethpandaops/x", Pattern: "*"}, wantErr: "org must be"},
+		{name: "no filter", source: GitHubSourceConfig{Org: "ethpandaops"}, wantErr: "pattern or topic is required"},
+		{name: "bad pattern", source: GitHubSourceConfig{Org: "ethpandaops", Pattern: "[-devnets"}, wantErr: "invalid pattern"},
+		{
+			name:    "bad paths",
+			source:  GitHubSourceConfig{Org: "ethpandaops", Topic: "devnet", Paths: RepositoryPaths{Active: "../x"}},
+			wantErr: "paths.active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.source.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// listingProvider is a provider that lists repositories and records the
+// repositories it was asked to discover.
+type listingProvider struct {
+	*MockProvider
+	listing      map[string][]SourceRepository
+	err          error
+	lists        int
+	repositories []string
+}
+
+func (p *listingProvider) ListRepositories(_ context.Context, _ Config, org string) ([]SourceRepository, error) {
+	p.lists++
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.listing[org], nil
+}
+
+func (p *listingProvider) Discover(ctx context.Context, config Config) (map[string]Network, error) {
+	p.repositories = p.repositories[:0]
+	for _, repo := range config.GitHub.Repositories {
+		p.repositories = append(p.repositories, repo.Name)
+	}
+
+	return p.MockProvider.Discover(ctx, config)
+}
+
+func TestDiscoveryService_ExpandsSources(t *testing.T) {
+	var config Config
+	config.GitHub.Sources = []GitHubSourceConfig{{Org: "ethpandaops", Pattern: "*-devnets"}}
+
+	service, err := NewService(logrus.New(), config, nil)
+	require.NoError(t, err)
+
+	provider := &listingProvider{
+		MockProvider: NewMockProvider("github", map[string]Network{
+			"fusaka-devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "active"},
+		}, nil),
+		listing: testListing(),
+	}
+	service.RegisterProvider(provider)
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ethpandaops/fusaka-devnets", "ethpandaops/glamsterdam-devnets"}, provider.repositories)
+	assert.Equal(t, "Fusaka Devnets", result.NetworkMetadata["fusaka"].DisplayName)
+
+	// Repositories of the sources can be triggered.
+	assert.True(t, service.Trigger("push", "ethpandaops/glamsterdam-devnets"))
+	service.clearTriggers()
+
+	// A failed listing reuses the last one.
+	provider.err = errors.New("unavailable")
+
+	_, err = service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, provider.lists)
+	assert.Equal(t, []string{"ethpandaops/fusaka-devnets", "ethpandaops/glamsterdam-devnets"}, provider.repositories)
+}
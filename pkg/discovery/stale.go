@@ -0,0 +1,209 @@
+package discovery
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"time"
+)
+
+// DefaultStaleGracePeriod is how long networks of a failed provider or
+// repository are reused when no grace period is configured.
+const DefaultStaleGracePeriod = 24 * time.Hour
+
+// goodNetwork is a network from a successful discovery and when it was discovered.
+type goodNetwork struct {
+	network Network
+	at      time.Time
+}
+
+// Seed primes the service with a previously published result, so that stale
+// fallback, last-success tracking and network LastUpdated times survive
+// restarts (e.g. in --once mode). Providers without network names in the
+// result are ignored.
+func (s *Service) Seed(result Result) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.seedVersions(result.Networks)
+
+	for _, p := range result.Providers {
+		if p.LastSuccess != nil {
+			s.lastSuccess[p.Name] = *p.LastSuccess
+		}
+
+		if len(p.NetworkNames) == 0 {
+			continue
+		}
+
+		good := make(map[string]goodNetwork, len(p.NetworkNames))
+
+		for _, name := range p.NetworkNames {
+			network, ok := result.Networks[name]
+			if !ok {
+				continue
+			}
+
+			at := result.LastUpdate
+			if network.Stale != nil {
+				at = network.Stale.Since
+			}
+
+			network.Stale = nil
+			good[name] = goodNetwork{network: network, at: at}
+		}
+
+		s.lastGood[p.Name] = good
+	}
+
+	s.log.WithField("providers", len(result.Providers)).Debug("Seeded discovery service from previous result")
+}
+
+// resolveProviderResult records the outcome of a provider run. It returns the
+// freshly discovered networks, the last good networks reused as stale for any
+// failed provider or repository, and the provider's serializable info.
+// carried are last good networks that a scoped run didn't rescan; they count
+// as fresh but keep the time they were discovered.
+func (s *Service) resolveProviderResult(
+	name string,
+	networks map[string]Network,
+	carried map[string]goodNetwork,
+	duration time.Duration,
+	err error,
+	now time.Time,
+) (fresh, stale map[string]Network, info ProviderInfo) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var (
+		previous = s.lastGood[name]
+		repoErrs RepositoryErrors
+	)
+
+	fresh = make(map[string]Network, len(networks))
+	stale = make(map[string]Network)
+	info = ProviderInfo{
+		Name:     name,
+		Status:   ProviderStatusSuccess,
+		Duration: duration.Seconds(),
+	}
+
+	s.lastStatus[name] = ProviderStatusFailed
+
+	switch {
+	case err == nil:
+		maps.Copy(fresh, networks)
+
+		good := newGoodNetworks(networks, now)
+		carry(fresh, good, carried)
+
+		s.lastGood[name] = good
+		s.lastSuccess[name] = now
+		s.lastStatus[name] = ProviderStatusSuccess
+	case errors.As(err, &repoErrs):
+		s.lastStatus[name] = ProviderStatusPartial
+		info.Status = ProviderStatusPartial
+		info.Error = err.Error()
+		info.FailedRepositories = make(map[string]string, len(repoErrs))
+
+		for repo, repoErr := range repoErrs {
+			info.FailedRepositories[repo] = repoErr.Error()
+		}
+
+		maps.Copy(fresh, networks)
+
+		good := newGoodNetworks(networks, now)
+		carry(fresh, good, carried)
+
+		// Reuse the last good networks of the repositories that failed.
+		for netName, g := range previous {
+			repoErr, failed := repoErrs[g.network.Repository]
+			if !failed {
+				continue
+			}
+
+			if _, rediscovered := networks[netName]; rediscovered || !s.withinGracePeriod(g, now) {
+				continue
+			}
+
+			good[netName] = g
+			stale[netName] = markStale(g, now, repoErr)
+		}
+
+		s.lastGood[name] = good
+	default:
+		info.Status = ProviderStatusFailed
+		info.Error = err.Error()
+
+		// Reuse all last good networks of the provider, keeping the snapshot as
+		// is so that the stale age keeps growing until the grace period ends.
+		for netName, g := range previous {
+			if s.withinGracePeriod(g, now) {
+				stale[netName] = markStale(g, now, err)
+			}
+		}
+	}
+
+	info.Networks = len(fresh) + len(stale)
+	info.StaleNetworks = len(stale)
+	info.NetworkNames = slices.Sorted(maps.Keys(fresh))
+	info.NetworkNames = append(info.NetworkNames, slices.Sorted(maps.Keys(stale))...)
+
+	if lastSuccess, ok := s.lastSuccess[name]; ok {
+		info.LastSuccess = &lastSuccess
+	}
+
+	if len(stale) > 0 {
+		s.log.WithFields(map[string]any{
+			"provider": name,
+			"networks": len(stale),
+		}).Warn("Reusing last good networks as stale")
+	}
+
+	return fresh, stale, info
+}
+
+// withinGracePeriod returns true if a last good network may still be reused.
+func (s *Service) withinGracePeriod(g goodNetwork, now time.Time) bool {
+	// A negative grace period disables the fallback.
+	if s.config.StaleGracePeriod < 0 {
+		return false
+	}
+
+	return now.Sub(g.at) <= s.config.StaleGracePeriod
+}
+
+// newGoodNetworks records networks as last good at the given time.
+func newGoodNetworks(networks map[string]Network, at time.Time) map[string]goodNetwork {
+	good := make(map[string]goodNetwork, len(networks))
+
+	for name, network := range networks {
+		good[name] = goodNetwork{network: network, at: at}
+	}
+
+	return good
+}
+
+// carry adds carried networks to fresh and good, unless they were rediscovered.
+func carry(fresh map[string]Network, good, carried map[string]goodNetwork) {
+	for name, g := range carried {
+		if _, rediscovered := fresh[name]; rediscovered {
+			continue
+		}
+
+		fresh[name] = g.network
+		good[name] = g
+	}
+}
+
+// markStale returns a copy of a last good network marked as stale.
+func markStale(g goodNetwork, now time.Time, reason error) Network {
+	network := g.network
+	network.Stale = &StaleInfo{
+		Since:  g.at,
+		Age:    now.Sub(g.at).Round(time.Second).Seconds(),
+		Reason: reason.Error(),
+	}
+
+	return network
+}
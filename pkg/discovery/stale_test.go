@@ -0,0 +1,164 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleFallback_ProviderFailure(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	provider := NewMockProvider("github", map[string]Network{
+		"devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "active"},
+	}, nil)
+	service.RegisterProvider(provider)
+
+	_, err = service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	provider.err = fmt.Errorf("rate limited")
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	require.Contains(t, result.Networks, "devnet-1")
+
+	network := result.Networks["devnet-1"]
+	require.NotNil(t, network.Stale)
+	assert.Equal(t, "rate limited", network.Stale.Reason)
+	assert.GreaterOrEqual(t, network.Stale.Age, float64(0))
+
+	info := result.Providers[0]
+	assert.Equal(t, ProviderStatusFailed, info.Status)
+	assert.Equal(t, 1, info.Networks)
+	assert.Equal(t, 1, info.StaleNetworks)
+	assert.Equal(t, []string{"devnet-1"}, info.NetworkNames)
+	assert.True(t, result.Partial)
+}
+
+func TestStaleFallback_GracePeriodExpired(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{StaleGracePeriod: time.Hour}, nil)
+	require.NoError(t, err)
+
+	service.RegisterProvider(NewMockProvider("github", nil, fmt.Errorf("boom")))
+
+	lastSuccess := time.Now().Add(-2 * time.Hour)
+	service.Seed(Result{
+		LastUpdate: lastSuccess,
+		Networks: map[string]Network{
+			"devnet-1": {Name: "devnet-1"},
+		},
+		Providers: []ProviderInfo{
+			{Name: "github", Status: ProviderStatusSuccess, LastSuccess: &lastSuccess, NetworkNames: []string{"devnet-1"}},
+		},
+	})
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Networks)
+	require.NotNil(t, result.Providers[0].LastSuccess)
+	assert.Equal(t, lastSuccess, *result.Providers[0].LastSuccess)
+}
+
+func TestStaleFallback_Disabled(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{StaleGracePeriod: -1}, nil)
+	require.NoError(t, err)
+
+	provider := NewMockProvider("github", map[string]Network{
+		"devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "active"},
+	}, nil)
+	service.RegisterProvider(provider)
+
+	_, err = service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	provider.err = fmt.Errorf("rate limited")
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Networks)
+	assert.Equal(t, ProviderStatusFailed, result.Providers[0].Status)
+}
+
+func TestStaleFallback_RepositoryFailure(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	provider := NewMockProvider("github", map[string]Network{
+		"fusaka-devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets"},
+		"glamsterdam-1":   {Name: "devnet-1", Repository: "ethpandaops/glamsterdam-devnets"},
+	}, nil)
+	service.RegisterProvider(provider)
+
+	_, err = service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	// The fusaka repository now fails, while glamsterdam still succeeds.
+	provider.networks = map[string]Network{
+		"glamsterdam-1": {Name: "devnet-1", Repository: "ethpandaops/glamsterdam-devnets"},
+	}
+	provider.err = RepositoryErrors{"ethpandaops/fusaka-devnets": fmt.Errorf("not found")}
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.Networks, 2)
+	assert.Nil(t, result.Networks["glamsterdam-1"].Stale)
+	require.NotNil(t, result.Networks["fusaka-devnet-1"].Stale)
+	assert.Equal(t, "not found", result.Networks["fusaka-devnet-1"].Stale.Reason)
+
+	info := result.Providers[0]
+	assert.Equal(t, ProviderStatusPartial, info.Status)
+	assert.Equal(t, map[string]string{"ethpandaops/fusaka-devnets": "not found"}, info.FailedRepositories)
+	assert.Equal(t, 1, info.StaleNetworks)
+	assert.True(t, result.Partial)
+}
+
+func TestStaleFallback_DoesNotOverrideFreshNetworks(t *testing.T) {
+	service, err := NewService(logrus.New(), Config{}, nil)
+	require.NoError(t, err)
+
+	github := NewMockProvider("github", map[string]Network{"devnet-1": {Name: "devnet-1", Status: "inactive"}}, nil)
+	static := NewMockProvider("static", map[string]Network{"devnet-1": {Name: "devnet-1", Status: "active"}}, nil)
+
+	service.RegisterProvider(github)
+	service.RegisterProvider(static)
+
+	_, err = service.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	// Static stops returning the network, github fails: the stale github copy is used.
+	static.networks = map[string]Network{}
+	github.err = fmt.Errorf("boom")
+
+	result, err := service.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, result.Networks["devnet-1"].Stale)
+	assert.Equal(t, "inactive", result.Networks["devnet-1"].Status)
+
+	// Static returns it again: the fresh copy wins over the stale one.
+	static.networks = map[string]Network{"devnet-1": {Name: "devnet-1", Status: "active"}}
+
+	result, err = service.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, result.Networks["devnet-1"].Stale)
+	assert.Equal(t, "active", result.Networks["devnet-1"].Status)
+}
+
+func TestRepositoryErrors_Error(t *testing.T) {
+	err := RepositoryErrors{
+		"ethpandaops/b-devnets": fmt.Errorf("second"),
+		"ethpandaops/a-devnets": fmt.Errorf("first"),
+	}
+
+	assert.Equal(t, "failed to discover 2 repositories: ethpandaops/a-devnets: first; ethpandaops/b-devnets: second", err.Error())
+}
@@ -0,0 +1,178 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultTriggerDebounce is how long triggered runs wait for further triggers
+// when no debounce is configured.
+const DefaultTriggerDebounce = 10 * time.Second
+
+// ScopedProvider is implemented by providers that can rediscover only some of
+// their repositories, so that triggered runs such as GitHub push webhooks
+// don't rescan everything.
+type ScopedProvider interface {
+	Provider
+
+	// DiscoverRepositories discovers networks from the given repositories
+	// only. Repositories the provider isn't configured for are ignored.
+	DiscoverRepositories(ctx context.Context, config Config, repositories []string) (map[string]Network, error)
+}
+
+// pendingTrigger accumulates triggers until the debounce timer fires.
+type pendingTrigger struct {
+	full         bool
+	repositories map[string]struct{}
+	reasons      map[string]struct{}
+}
+
+// Trigger requests a discovery run outside of the interval. With repositories,
+// scoped providers only rescan those repositories; without, all providers run
+// fully. Triggers are debounced and coalesced into a single run. It returns
+// false if none of the repositories are configured, in which case nothing is
+// triggered.
+func (s *Service) Trigger(reason string, repositories ...string) bool {
+	config := s.currentConfig()
+	config.GitHub.Repositories = ExpandSources(config, s.listedRepositories())
+
+	if len(repositories) > 0 {
+		repositories = configuredRepositories(config, repositories)
+		if len(repositories) == 0 {
+			return false
+		}
+	}
+
+	s.triggerMutex.Lock()
+	defer s.triggerMutex.Unlock()
+
+	if s.pending == nil {
+		s.pending = &pendingTrigger{
+			repositories: make(map[string]struct{}),
+			reasons:      make(map[string]struct{}),
+		}
+	}
+
+	s.pending.reasons[reason] = struct{}{}
+
+	if len(repositories) == 0 {
+		s.pending.full = true
+	}
+
+	for _, repo := range repositories {
+		s.pending.repositories[repo] = struct{}{}
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"reason":       reason,
+		"repositories": repositories,
+		"debounce":     config.TriggerDebounce,
+	}).Info("Discovery triggered")
+
+	if s.triggerTimer == nil {
+		s.triggerTimer = time.AfterFunc(config.TriggerDebounce, s.fireTrigger)
+	} else {
+		s.triggerTimer.Reset(config.TriggerDebounce)
+	}
+
+	return true
+}
+
+// fireTrigger wakes up the discovery loop once the debounce period is over.
+func (s *Service) fireTrigger() {
+	select {
+	case s.triggerChan <- struct{}{}:
+	default:
+		// A wake-up is already queued and will pick up the pending trigger.
+	}
+}
+
+// takeTrigger returns and clears the pending trigger. scope is nil for a full
+// run.
+func (s *Service) takeTrigger() (scope []string, reasons []string, ok bool) {
+	s.triggerMutex.Lock()
+	defer s.triggerMutex.Unlock()
+
+	pending := s.pending
+	s.pending = nil
+
+	if pending == nil {
+		return nil, nil, false
+	}
+
+	reasons = slices.Sorted(maps.Keys(pending.reasons))
+
+	if pending.full {
+		return nil, reasons, true
+	}
+
+	return slices.Sorted(maps.Keys(pending.repositories)), reasons, true
+}
+
+// clearTriggers drops pending triggers, as a full run is about to start.
+func (s *Service) clearTriggers() {
+	s.triggerMutex.Lock()
+	defer s.triggerMutex.Unlock()
+
+	s.pending = nil
+}
+
+// configuredRepositories returns the configured GitHub repositories among
+// repositories, using their configured names.
+func configuredRepositories(config Config, repositories []string) []string {
+	configured := make([]string, 0, len(repositories))
+
+	for _, repo := range config.GitHub.Repositories {
+		for _, candidate := range repositories {
+			if strings.EqualFold(repo.Name, candidate) {
+				configured = append(configured, repo.Name)
+
+				break
+			}
+		}
+	}
+
+	return configured
+}
+
+// discoverScoped rediscovers the given repositories with a scoped provider and
+// carries over its last good networks from all other repositories.
+func (s *Service) discoverScoped(
+	ctx context.Context,
+	config Config,
+	p ScopedProvider,
+	scope []string,
+) (networks map[string]Network, carried map[string]goodNetwork, warnings []Warning, err error) {
+	networks, warnings, err = discover(ctx, config, p, scope)
+
+	// Repositories in scope fall back to their last good networks on failure,
+	// the carried networks of other repositories stay valid either way.
+	var repoErrs RepositoryErrors
+	if err != nil && !errors.As(err, &repoErrs) {
+		repoErrs = make(RepositoryErrors, len(scope))
+		for _, repo := range scope {
+			repoErrs[repo] = err
+		}
+
+		err = repoErrs
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	carried = make(map[string]goodNetwork)
+
+	for name, g := range s.lastGood[p.Name()] {
+		if !slices.Contains(scope, g.network.Repository) {
+			carried[name] = g
+		}
+	}
+
+	return networks, carried, warnings, err
+}
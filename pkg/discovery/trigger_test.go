@@ -0,0 +1,148 @@
+package discovery
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scopedMockProvider discovers one network per repository, with the number of
+// the run as its description.
+type scopedMockProvider struct {
+	mutex  sync.Mutex
+	runs   int
+	scopes [][]string
+}
+
+func (p *scopedMockProvider) Name() string {
+	return "github"
+}
+
+func (p *scopedMockProvider) Discover(ctx context.Context, config Config) (map[string]Network, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.runs++
+
+	networks := make(map[string]Network)
+
+	for _, repo := range config.GitHub.Repositories {
+		networks[repo.Name] = Network{Name: repo.Name, Repository: repo.Name, Description: strconv.Itoa(p.runs)}
+	}
+
+	return networks, nil
+}
+
+func (p *scopedMockProvider) DiscoverRepositories(ctx context.Context, config Config, repositories []string) (map[string]Network, error) {
+	p.mutex.Lock()
+	p.scopes = append(p.scopes, repositories)
+	p.mutex.Unlock()
+
+	config.GitHub.Repositories = nil
+	for _, repo := range repositories {
+		config.GitHub.Repositories = append(config.GitHub.Repositories, GitHubRepositoryConfig{Name: repo})
+	}
+
+	return p.Discover(ctx, config)
+}
+
+func newTriggerTestService(t *testing.T) (*Service, *scopedMockProvider, chan Result) {
+	t.Helper()
+
+	config := Config{
+		Interval:        time.Hour,
+		TriggerDebounce: 20 * time.Millisecond,
+	}
+	config.GitHub.Repositories = []GitHubRepositoryConfig{{Name: "org/a"}, {Name: "org/b"}}
+
+	service, err := NewService(logrus.New(), config, nil)
+	require.NoError(t, err)
+
+	provider := &scopedMockProvider{}
+	service.RegisterProvider(provider)
+
+	results := make(chan Result, 10)
+	service.OnResult(func(result Result) {
+		results <- result
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = service.Stop(context.Background())
+	})
+
+	require.NoError(t, service.Start(ctx))
+
+	// Initial full run
+	waitForResult(t, results)
+
+	return service, provider, results
+}
+
+func waitForResult(t *testing.T, results chan Result) Result {
+	t.Helper()
+
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "timed out waiting for discovery result")
+
+		return Result{}
+	}
+}
+
+func TestTrigger_ScopedRunCarriesOtherRepositories(t *testing.T) {
+	service, provider, results := newTriggerTestService(t)
+
+	// Coalesced into one run, repository names are matched case-insensitively.
+	assert.True(t, service.Trigger("webhook", "ORG/A"))
+	assert.True(t, service.Trigger("webhook", "org/a"))
+
+	result := waitForResult(t, results)
+
+	assert.Equal(t, "2", result.Networks["org/a"].Description, "rescanned")
+	assert.Equal(t, "1", result.Networks["org/b"].Description, "carried over")
+	assert.Equal(t, ProviderStatusSuccess, result.Providers[0].Status)
+
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	assert.Equal(t, [][]string{{"org/a"}}, provider.scopes)
+}
+
+func TestTrigger_FullTriggerWins(t *testing.T) {
+	service, provider, results := newTriggerTestService(t)
+
+	assert.True(t, service.Trigger("webhook", "org/a"))
+	assert.True(t, service.Trigger("sighup"))
+
+	result := waitForResult(t, results)
+
+	assert.Equal(t, "2", result.Networks["org/a"].Description)
+	assert.Equal(t, "2", result.Networks["org/b"].Description)
+
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	assert.Empty(t, provider.scopes)
+}
+
+func TestTrigger_UnknownRepository(t *testing.T) {
+	service, _, results := newTriggerTestService(t)
+
+	assert.False(t, service.Trigger("webhook", "other/repo"))
+
+	select {
+	case <-results:
+		assert.Fail(t, "unexpected discovery run")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -2,10 +2,14 @@ package discovery
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
-// Network represents an Ethereum network.
+// Network represents an Ethereum network. LastUpdated and Hash are set by the
+// discovery service, LastUpdated only moves when the network's Hash changes.
 type Network struct {
 	Name          string         `json:"name"`
 	Repository    string         `json:"repository,omitempty"`
@@ -15,14 +19,95 @@ type Network struct {
 	Links         []Link         `json:"links,omitempty"`
 	Status        string         `json:"status"`
 	LastUpdated   time.Time      `json:"lastUpdated"`
+	Hash          string         `json:"hash,omitempty"`
 	ChainID       uint64         `json:"chainId,omitempty"`
 	GenesisConfig *GenesisConfig `json:"genesisConfig,omitempty"`
 	ServiceURLs   *ServiceURLs   `json:"serviceUrls,omitempty"`
 	Images        *Images        `json:"images,omitempty"`
 	HiveURL       string         `json:"hiveUrl,omitempty"`
 	SelfHostedDNS bool           `json:"selfHostedDns"`
+	Applications  []Application  `json:"applications,omitempty"`
 	Forks         *ForksConfig   `json:"forks,omitempty"`
 	BlobSchedule  []BlobSchedule `json:"blobSchedule,omitempty"`
+	ConsensusSpec *ConsensusSpec `json:"consensusSpec,omitempty"`
+	ExecutionSpec *ExecutionSpec `json:"executionSpec,omitempty"`
+	Stale         *StaleInfo     `json:"stale,omitempty"`
+}
+
+// ConsensusSpec holds the consensus-layer config (config.yaml) of a network.
+// The yaml tags are the config.yaml keys. Fork epochs and the blob schedule
+// are in Network.Forks and Network.BlobSchedule.
+type ConsensusSpec struct {
+	PresetBase string `json:"presetBase,omitempty" yaml:"PRESET_BASE"`
+	ConfigName string `json:"configName,omitempty" yaml:"CONFIG_NAME"`
+	// ForkVersions are the fork versions keyed by lower-case fork name, e.g.
+	// "genesis" for GENESIS_FORK_VERSION.
+	ForkVersions map[string]string `json:"forkVersions,omitempty" yaml:"-"`
+
+	// Transition.
+	TerminalTotalDifficulty          string `json:"terminalTotalDifficulty,omitempty" yaml:"TERMINAL_TOTAL_DIFFICULTY"`
+	TerminalBlockHash                string `json:"terminalBlockHash,omitempty" yaml:"TERMINAL_BLOCK_HASH"`
+	TerminalBlockHashActivationEpoch uint64 `json:"terminalBlockHashActivationEpoch,omitempty" yaml:"TERMINAL_BLOCK_HASH_ACTIVATION_EPOCH"`
+
+	// Genesis.
+	MinGenesisActiveValidatorCount uint64 `json:"minGenesisActiveValidatorCount,omitempty" yaml:"MIN_GENESIS_ACTIVE_VALIDATOR_COUNT"`
+	MinGenesisTime                 uint64 `json:"minGenesisTime,omitempty" yaml:"MIN_GENESIS_TIME"`
+	GenesisDelay                   uint64 `json:"genesisDelay,omitempty" yaml:"GENESIS_DELAY"`
+
+	// Time parameters.
+	SecondsPerSlot                   uint64 `json:"secondsPerSlot,omitempty" yaml:"SECONDS_PER_SLOT"`
+	SlotDurationMS                   uint64 `json:"slotDurationMs,omitempty" yaml:"SLOT_DURATION_MS"`
+	SlotsPerEpoch                    uint64 `json:"slotsPerEpoch,omitempty" yaml:"SLOTS_PER_EPOCH"`
+	SecondsPerEth1Block              uint64 `json:"secondsPerEth1Block,omitempty" yaml:"SECONDS_PER_ETH1_BLOCK"`
+	MinValidatorWithdrawabilityDelay uint64 `json:"minValidatorWithdrawabilityDelay,omitempty" yaml:"MIN_VALIDATOR_WITHDRAWABILITY_DELAY"`
+	ShardCommitteePeriod             uint64 `json:"shardCommitteePeriod,omitempty" yaml:"SHARD_COMMITTEE_PERIOD"`
+	Eth1FollowDistance               uint64 `json:"eth1FollowDistance,omitempty" yaml:"ETH1_FOLLOW_DISTANCE"`
+
+	// Validator cycle.
+	InactivityScoreBias                 uint64 `json:"inactivityScoreBias,omitempty" yaml:"INACTIVITY_SCORE_BIAS"`
+	InactivityScoreRecoveryRate         uint64 `json:"inactivityScoreRecoveryRate,omitempty" yaml:"INACTIVITY_SCORE_RECOVERY_RATE"`
+	EjectionBalance                     uint64 `json:"ejectionBalance,omitempty" yaml:"EJECTION_BALANCE"`
+	MinPerEpochChurnLimit               uint64 `json:"minPerEpochChurnLimit,omitempty" yaml:"MIN_PER_EPOCH_CHURN_LIMIT"`
+	ChurnLimitQuotient                  uint64 `json:"churnLimitQuotient,omitempty" yaml:"CHURN_LIMIT_QUOTIENT"`
+	MaxPerEpochActivationChurnLimit     uint64 `json:"maxPerEpochActivationChurnLimit,omitempty" yaml:"MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT"`
+	MinPerEpochChurnLimitElectra        uint64 `json:"minPerEpochChurnLimitElectra,omitempty" yaml:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA"`
+	MaxPerEpochActivationExitChurnLimit uint64 `json:"maxPerEpochActivationExitChurnLimit,omitempty" yaml:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT"`
+
+	// Deposit contract.
+	DepositChainID         uint64 `json:"depositChainId,omitempty" yaml:"DEPOSIT_CHAIN_ID"`
+	DepositNetworkID       uint64 `json:"depositNetworkId,omitempty" yaml:"DEPOSIT_NETWORK_ID"`
+	DepositContractAddress string `json:"depositContractAddress,omitempty" yaml:"DEPOSIT_CONTRACT_ADDRESS"`
+
+	// Blobs.
+	MaxBlobsPerBlock        uint64 `json:"maxBlobsPerBlock,omitempty" yaml:"MAX_BLOBS_PER_BLOCK"`
+	MaxBlobsPerBlockElectra uint64 `json:"maxBlobsPerBlockElectra,omitempty" yaml:"MAX_BLOBS_PER_BLOCK_ELECTRA"`
+
+	// Extra holds the keys of config.yaml not covered above, by their
+	// config.yaml name. Hex values are kept as strings.
+	Extra map[string]any `json:"extra,omitempty" yaml:"-"`
+}
+
+// ExecutionSpec holds values of the execution-layer genesis of a network.
+type ExecutionSpec struct {
+	GasLimit               uint64 `json:"gasLimit,omitempty"`
+	DepositContractAddress string `json:"depositContractAddress,omitempty"`
+	// TerminalTotalDifficulty is a decimal number, as it can exceed 64 bits.
+	TerminalTotalDifficulty string `json:"terminalTotalDifficulty,omitempty"`
+}
+
+// Application is an application deployed for a network, with the hosts of
+// its ingresses.
+type Application struct {
+	Name  string   `json:"name"`
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// StaleInfo marks a network that was reused from a previous discovery run
+// because its provider or repository failed in the current run.
+type StaleInfo struct {
+	Since  time.Time `json:"since"`
+	Age    float64   `json:"age"`
+	Reason string    `json:"reason,omitempty"`
 }
 
 // Link represents a related link with title and URL.
@@ -101,19 +186,88 @@ type ClientInfo struct {
 	DocsURL       string `json:"docsUrl,omitempty"`
 }
 
-// ProviderInfo represents serializable information about a provider.
+// Provider run statuses reported in ProviderInfo.
+const (
+	ProviderStatusSuccess = "success"
+	ProviderStatusPartial = "partial"
+	ProviderStatusFailed  = "failed"
+)
+
+// ProviderInfo represents serializable information about a provider and the
+// outcome of its most recent discovery run.
 type ProviderInfo struct {
-	Name string `json:"name"`
+	Name               string            `json:"name"`
+	Status             string            `json:"status"`
+	Error              string            `json:"error,omitempty"`
+	FailedRepositories map[string]string `json:"failedRepositories,omitempty"`
+	Duration           float64           `json:"duration"`
+	Networks           int               `json:"networks"`
+	StaleNetworks      int               `json:"staleNetworks,omitempty"`
+	NetworkNames       []string          `json:"networkNames,omitempty"`
+	LastSuccess        *time.Time        `json:"lastSuccess,omitempty"`
+}
+
+// Failed returns true if the provider did not fully complete its last discovery
+// run, either failing outright or failing for some of its repositories.
+func (p ProviderInfo) Failed() bool {
+	return p.Status != ProviderStatusSuccess
+}
+
+// RateLimit is the remaining budget of a GitHub API rate-limit resource, such
+// as "core", at the end of a discovery run.
+type RateLimit struct {
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
 }
 
 // Result represents the result of a discovery operation.
 type Result struct {
+	// SchemaVersion is the version of this format, see SchemaVersion.
+	SchemaVersion   string                        `json:"schemaVersion"`
 	NetworkMetadata map[string]RepositoryMetadata `json:"networkMetadata"`
 	Networks        map[string]Network            `json:"networks"`
 	Clients         map[string]ClientInfo         `json:"clients"`
 	LastUpdate      time.Time                     `json:"lastUpdate"`
 	Duration        float64                       `json:"duration"`
 	Providers       []ProviderInfo                `json:"providers"`
+	// Partial is true when at least one provider failed, meaning networks from
+	// that provider may be missing from this result.
+	Partial bool `json:"partial"`
+	// Conflicts lists the networks discovered by more than one provider and
+	// how they were merged.
+	Conflicts []MergeConflict `json:"conflicts,omitempty"`
+	// RateLimits is the GitHub API budget left after the run.
+	RateLimits []RateLimit `json:"rateLimits,omitempty"`
+	// Warnings lists problems that didn't fail discovery, such as files in a
+	// layout that isn't understood.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// FailedProviders returns the providers that failed, entirely or partially,
+// during this discovery run.
+func (r Result) FailedProviders() []ProviderInfo {
+	failed := make([]ProviderInfo, 0)
+
+	for _, p := range r.Providers {
+		if p.Failed() {
+			failed = append(failed, p)
+		}
+	}
+
+	return failed
+}
+
+// GitHubConfig represents the configuration for GitHub discovery.
+type GitHubConfig struct {
+	Repositories []GitHubRepositoryConfig `mapstructure:"repositories"`
+	// Sources expand to more repositories at discovery time.
+	Sources []GitHubSourceConfig `mapstructure:"sources"`
+	Token   string               `mapstructure:"token"`
+	// Concurrency bounds how many repositories, networks and requests are
+	// processed at once.
+	Concurrency GitHubConcurrencyConfig `mapstructure:"concurrency"`
 }
 
 // GitHubRepositoryConfig represents the configuration for a GitHub repository source.
@@ -124,6 +278,11 @@ type GitHubRepositoryConfig struct {
 	Description string `mapstructure:"description"`
 	Image       string `mapstructure:"image"`
 	Links       []Link `mapstructure:"links"`
+	// Ref is the branch or tag to discover networks from. Defaults to the
+	// default branch of the repository.
+	Ref string `mapstructure:"ref"`
+	// Paths overrides the directory layout of the repository.
+	Paths RepositoryPaths `mapstructure:"paths"`
 }
 
 // StaticNetworkConfig represents the configuration for a static network.
@@ -139,6 +298,17 @@ type StaticNetworkConfig struct {
 	ServiceURLs         map[string]string `mapstructure:"serviceUrls"`
 	Forks               *ForksConfig      `mapstructure:"forks"`
 	BlobSchedule        []BlobSchedule    `mapstructure:"blobSchedule"`
+	// SlotTiming changes the slot timing from consensus forks on.
+	SlotTiming []StaticSlotTimingConfig `mapstructure:"slotTiming"`
+}
+
+// StaticSlotTimingConfig changes the slot timing of a static network from the
+// epoch of a consensus fork on. Values that aren't set keep the timing from
+// before the fork.
+type StaticSlotTimingConfig struct {
+	Fork                string `mapstructure:"fork"`
+	SlotsPerEpoch       uint64 `mapstructure:"slotsPerEpoch"`
+	SlotDurationSeconds uint64 `mapstructure:"slotDurationSeconds"`
 }
 
 // ForksConfig represents fork configuration for both consensus and execution layers.
@@ -166,10 +336,21 @@ type Config struct {
 	Static   struct {
 		Networks []StaticNetworkConfig `mapstructure:"networks"`
 	} `mapstructure:"static"`
-	GitHub struct {
-		Repositories []GitHubRepositoryConfig `mapstructure:"repositories"`
-		Token        string                   `mapstructure:"token"`
-	} `mapstructure:"github"`
+	GitHub GitHubConfig `mapstructure:"github"`
+	// StaleGracePeriod is how long the last good networks of a failed provider
+	// or repository are reused (marked as stale) before they are dropped.
+	// Defaults to DefaultStaleGracePeriod; a negative value disables the
+	// fallback, dropping the networks right away.
+	StaleGracePeriod time.Duration `mapstructure:"staleGracePeriod"`
+	// Providers enables discovery providers by name and sets their priority and
+	// timeout. If empty, all registered providers are enabled.
+	Providers map[string]ProviderConfig `mapstructure:"providers"`
+	// TriggerDebounce is how long a triggered run waits for further triggers,
+	// which are coalesced into the same run.
+	TriggerDebounce time.Duration `mapstructure:"triggerDebounce"`
+	// Merge configures field-level merging of networks discovered by several
+	// providers.
+	Merge MergeConfig `mapstructure:"merge"`
 }
 
 // Provider is the interface that all discovery providers must implement.
@@ -181,6 +362,28 @@ type Provider interface {
 	Discover(ctx context.Context, config Config) (map[string]Network, error)
 }
 
+// RepositoryErrors is returned by providers that completed discovery but failed
+// for some of their repositories. Networks returned alongside it are valid; the
+// service falls back to the last good networks of the failed repositories.
+type RepositoryErrors map[string]error
+
+// Error implements the error interface.
+func (e RepositoryErrors) Error() string {
+	repos := make([]string, 0, len(e))
+	for repo := range e {
+		repos = append(repos, repo)
+	}
+
+	slices.Sort(repos)
+
+	msgs := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", repo, e[repo]))
+	}
+
+	return fmt.Sprintf("failed to discover %d repositories: %s", len(e), strings.Join(msgs, "; "))
+}
+
 // ResultHandler is a function that handles discovery results.
 type ResultHandler func(Result)
 
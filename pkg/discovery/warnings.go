@@ -0,0 +1,71 @@
+package discovery
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// Warning is a problem found during discovery that didn't fail it, such as a
+// file in a layout that isn't understood.
+type Warning struct {
+	Provider   string `json:"provider"`
+	Repository string `json:"repository,omitempty"`
+	Network    string `json:"network,omitempty"`
+	File       string `json:"file,omitempty"`
+	Message    string `json:"message"`
+}
+
+// WarningProvider is implemented by providers that report warnings alongside
+// the networks they discovered.
+type WarningProvider interface {
+	Provider
+
+	// DiscoverWithWarnings is Discover, or DiscoverRepositories of a
+	// ScopedProvider if repositories is not nil, also returning the warnings
+	// of the run. The service fills in their provider.
+	DiscoverWithWarnings(ctx context.Context, config Config, repositories []string) (map[string]Network, []Warning, error)
+}
+
+// discover runs p, on the given repositories only if they are not nil, which
+// requires p to be a ScopedProvider. Warnings are returned for providers
+// implementing WarningProvider.
+func discover(ctx context.Context, config Config, p Provider, repositories []string) (map[string]Network, []Warning, error) {
+	var (
+		networks map[string]Network
+		warnings []Warning
+		err      error
+	)
+
+	warner, isWarner := p.(WarningProvider)
+	scoped, isScoped := p.(ScopedProvider)
+
+	switch {
+	case isWarner:
+		networks, warnings, err = warner.DiscoverWithWarnings(ctx, config, repositories)
+	case isScoped && repositories != nil:
+		networks, err = scoped.DiscoverRepositories(ctx, config, repositories)
+	default:
+		networks, err = p.Discover(ctx, config)
+	}
+
+	for i := range warnings {
+		warnings[i].Provider = p.Name()
+	}
+
+	return networks, warnings, err
+}
+
+// sortWarnings sorts warnings, so the result doesn't depend on the order in
+// which they were found.
+func sortWarnings(warnings []Warning) {
+	slices.SortFunc(warnings, func(a, b Warning) int {
+		return cmp.Or(
+			cmp.Compare(a.Provider, b.Provider),
+			cmp.Compare(a.Repository, b.Repository),
+			cmp.Compare(a.Network, b.Network),
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+}
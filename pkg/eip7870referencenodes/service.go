@@ -10,7 +10,11 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
 
+	"github.com/ethpandaops/cartographoor/pkg/githubapi"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
 	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
 )
 
@@ -23,7 +27,6 @@ type Service struct {
 	platformParser *PlatformParser
 	commandBuilder *CommandBuilder
 	logger         logrus.FieldLogger
-	githubToken    string
 }
 
 // NewService creates a new EIP-7870 reference nodes service.
@@ -31,19 +34,17 @@ func NewService(
 	log logrus.FieldLogger,
 	config *Config,
 	s3Storage *s3.Provider,
-	githubToken string,
+	auth oauth2.TokenSource,
+	cache *httpcache.Cache,
 ) *Service {
 	return &Service{
-		config:    config,
-		s3Storage: s3Storage,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		config:         config,
+		s3Storage:      s3Storage,
+		httpClient:     githubapi.NewContentHTTPClient(auth, cache, 30*time.Second),
 		helmParser:     NewHelmChartParser(config.PortOverrides),
 		platformParser: NewPlatformParser(config.SecretPatterns),
 		commandBuilder: NewCommandBuilder(),
 		logger:         log.WithField("module", "eip7870_reference_nodes"),
-		githubToken:    githubToken,
 	}
 }
 
@@ -93,6 +94,8 @@ func (s *Service) Generate(ctx context.Context) error {
 		result.ReferenceNodes[client] = cmd
 	}
 
+	metrics.ObserveGeneratedItems(metrics.GeneratorEIP7870ReferenceNodes, len(result.ReferenceNodes), len(clients)-len(result.ReferenceNodes))
+
 	if len(result.ReferenceNodes) == 0 {
 		return fmt.Errorf("no clients were successfully processed")
 	}
@@ -195,11 +198,6 @@ func (s *Service) fetchURL(ctx context.Context, url string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add GitHub token if available
-	if s.githubToken != "" {
-		req.Header.Set("Authorization", "token "+s.githubToken)
-	}
-
 	req.Header.Set("Accept", "application/vnd.github.v3.raw")
 
 	resp, err := s.httpClient.Do(req)
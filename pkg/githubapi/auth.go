@@ -0,0 +1,349 @@
+package githubapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v53/github"
+	"golang.org/x/oauth2"
+
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+)
+
+const (
+	// tokenRefreshMargin is how long before expiry an installation token is
+	// replaced. Installation tokens are valid for an hour.
+	tokenRefreshMargin = 5 * time.Minute
+
+	// tokenTimeout bounds minting an installation token.
+	tokenTimeout = 30 * time.Second
+
+	// jwtLifetime is the validity of the app JWTs; GitHub allows at most ten
+	// minutes.
+	jwtLifetime = 9 * time.Minute
+
+	// jwtClockSkew backdates the app JWTs to allow for clock drift.
+	jwtClockSkew = time.Minute
+)
+
+// Config represents the GitHub credentials shared by discovery and the
+// generators: either a personal access token or a GitHub App.
+type Config struct {
+	// Token is a personal access token.
+	Token string `mapstructure:"token"`
+
+	// App authenticates as a GitHub App installation.
+	App AppConfig `mapstructure:"app"`
+}
+
+// AppConfig represents the configuration of a GitHub App.
+type AppConfig struct {
+	// ID is the app ID.
+	ID int64 `mapstructure:"id"`
+
+	// InstallationID is the installation to mint tokens for. If not set, the
+	// app must be installed exactly once.
+	InstallationID int64 `mapstructure:"installationId"`
+
+	// PrivateKey is the PEM encoded private key of the app.
+	PrivateKey string `mapstructure:"privateKey"`
+
+	// PrivateKeyFile is read for the private key if PrivateKey is not set.
+	PrivateKeyFile string `mapstructure:"privateKeyFile"`
+}
+
+// IsSet returns true if any credentials are configured.
+func (c *Config) IsSet() bool {
+	return c.Token != "" || c.App.IsSet()
+}
+
+// IsSet returns true if the app is configured.
+func (c *AppConfig) IsSet() bool {
+	return c.ID != 0 || c.InstallationID != 0 || c.PrivateKey != "" || c.PrivateKeyFile != ""
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	if !c.App.IsSet() {
+		return nil
+	}
+
+	if c.Token != "" {
+		return errors.New("configure either token or app, not both")
+	}
+
+	if c.App.ID <= 0 {
+		return errors.New("app.id is required")
+	}
+
+	if c.App.InstallationID < 0 {
+		return fmt.Errorf("app.installationId must not be negative, got %d", c.App.InstallationID)
+	}
+
+	if (c.App.PrivateKey == "") == (c.App.PrivateKeyFile == "") {
+		return errors.New("exactly one of app.privateKey and app.privateKeyFile is required")
+	}
+
+	return nil
+}
+
+// NewTokenSource returns the token source of the configured credentials, or
+// nil if none are configured. Installation tokens of an app are minted on
+// first use and replaced before they expire.
+func NewTokenSource(config Config) (oauth2.TokenSource, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid github auth config: %w", err)
+	}
+
+	if !config.App.IsSet() {
+		return StaticTokenSource(config.Token), nil
+	}
+
+	source, err := newAppTokenSource(config.App, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentialTokenSource{
+		TokenSource: oauth2.ReuseTokenSourceWithExpiry(nil, source, tokenRefreshMargin),
+		// The installation stays the same when it is looked up, as the app
+		// must be installed exactly once.
+		credential: fmt.Sprintf("app/%d/installation/%d", config.App.ID, config.App.InstallationID),
+	}, nil
+}
+
+// StaticTokenSource returns a token source of a personal access token, or nil
+// if token is empty.
+func StaticTokenSource(token string) oauth2.TokenSource {
+	if token == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(token))
+
+	return &credentialTokenSource{
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+		credential:  "token/" + hex.EncodeToString(sum[:]),
+	}
+}
+
+// credentialTokenSource is a token source with a stable identity of its
+// credentials, under which the HTTP cache keeps responses across token
+// refreshes.
+type credentialTokenSource struct {
+	oauth2.TokenSource
+	credential string
+}
+
+// cacheTransport returns the cache transport for requests authenticated by
+// auth, keyed on the identity of its credentials if known and on the
+// Authorization header otherwise.
+func cacheTransport(cache *httpcache.Cache, base http.RoundTripper, auth oauth2.TokenSource) http.RoundTripper {
+	if source, ok := auth.(*credentialTokenSource); ok {
+		return cache.CredentialTransport(base, source.credential)
+	}
+
+	return cache.Transport(base)
+}
+
+// appTokenSource mints installation tokens of a GitHub App.
+type appTokenSource struct {
+	installationID int64
+	// client is authenticated as the app itself.
+	client *gh.Client
+}
+
+// newAppTokenSource creates an app token source. baseURL overrides the GitHub
+// API URL in tests.
+func newAppTokenSource(config AppConfig, baseURL *string) (*appTokenSource, error) {
+	keyPEM := []byte(config.PrivateKey)
+
+	if config.PrivateKeyFile != "" {
+		var err error
+
+		keyPEM, err = os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read github app private key: %w", err)
+		}
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	jwts := oauth2.ReuseTokenSourceWithExpiry(nil, &jwtSource{appID: config.ID, key: key, now: time.Now}, jwtClockSkew)
+
+	client := gh.NewClient(&http.Client{
+		Transport: &oauth2.Transport{Source: jwts, Base: &Transport{}},
+		Timeout:   tokenTimeout,
+	})
+
+	if baseURL != nil {
+		client.BaseURL, err = client.BaseURL.Parse(*baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid github api url: %w", err)
+		}
+	}
+
+	return &appTokenSource{
+		installationID: config.InstallationID,
+		client:         client,
+	}, nil
+}
+
+// Token implements oauth2.TokenSource. It is called by a reuse token source,
+// which serializes the calls.
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenTimeout)
+	defer cancel()
+
+	if s.installationID == 0 {
+		installations, _, err := s.client.Apps.ListInstallations(ctx, &gh.ListOptions{PerPage: 2})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list github app installations: %w", err)
+		}
+
+		if len(installations) != 1 {
+			return nil, fmt.Errorf("github app has %d installations, set app.installationId", len(installations))
+		}
+
+		s.installationID = installations[0].GetID()
+	}
+
+	token, _, err := s.client.Apps.CreateInstallationToken(ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github app installation token: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "Bearer",
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+// jwtSource signs the JWTs that authenticate as a GitHub App.
+type jwtSource struct {
+	appID int64
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+// Token implements oauth2.TokenSource.
+func (s *jwtSource) Token() (*oauth2.Token, error) {
+	now := s.now()
+	expiry := now.Add(jwtLifetime)
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode jwt header: %w", err)
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": expiry.Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode jwt claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign jwt: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: unsigned + "." + base64.RawURLEncoding.EncodeToString(signature),
+		TokenType:   "Bearer",
+		Expiry:      expiry,
+	}, nil
+}
+
+// parsePrivateKey parses a PEM encoded PKCS#1 or PKCS#8 RSA private key, as
+// downloaded from the GitHub App settings.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("github app private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github app private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app private key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+// githubHosts are the hosts that receive GitHub credentials on raw content
+// fetches.
+var githubHosts = []string{"github.com", "api.github.com", "raw.githubusercontent.com"}
+
+// NewContentHTTPClient returns an HTTP client for raw content fetches, such as
+// files on raw.githubusercontent.com. Requests to GitHub hosts are
+// authenticated with auth if it is not nil, requests to other hosts never are.
+// Responses are revalidated through cache, which may be nil.
+func NewContentHTTPClient(auth oauth2.TokenSource, cache *httpcache.Cache, timeout time.Duration) *http.Client {
+	// As for the API client, credentials go on top of the cache so they are part
+	// of its key.
+	transport := cache.Transport(nil)
+	if auth != nil {
+		transport = &githubHostTransport{
+			authenticated: &oauth2.Transport{Source: auth, Base: cacheTransport(cache, nil, auth)},
+			base:          transport,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// githubHostTransport authenticates requests to GitHub hosts only.
+type githubHostTransport struct {
+	authenticated http.RoundTripper
+	base          http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *githubHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Hostname())
+
+	for _, githubHost := range githubHosts {
+		if host == githubHost {
+			return t.authenticated.RoundTrip(req)
+		}
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,304 @@
+package githubapi
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "empty", config: Config{}},
+		{name: "token", config: Config{Token: "ghp_x"}},
+		{name: "app", config: Config{App: AppConfig{ID: 1, PrivateKeyFile: "key.pem"}}},
+		{
+			name:    "token and app",
+			config:  Config{Token: "ghp_x", App: AppConfig{ID: 1, PrivateKeyFile: "key.pem"}},
+			wantErr: "either token or app",
+		},
+		{
+			name:    "app without id",
+			config:  Config{App: AppConfig{PrivateKeyFile: "key.pem"}},
+			wantErr: "app.id is required",
+		},
+		{
+			name:    "app without key",
+			config:  Config{App: AppConfig{ID: 1}},
+			wantErr: "exactly one of app.privateKey and app.privateKeyFile",
+		},
+		{
+			name:    "app with two keys",
+			config:  Config{App: AppConfig{ID: 1, PrivateKey: "-----BEGIN", PrivateKeyFile: "key.pem"}},
+			wantErr: "exactly one of app.privateKey and app.privateKeyFile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// appServer serves the app installation endpoints, verifying the JWTs with
+// key. Tokens expire after expiresIn.
+func appServer(t *testing.T, key *rsa.PrivateKey, expiresIn time.Duration, minted *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.NoError(t, verifyJWT(&key.PublicKey, r.Header.Get("Authorization"), "123")) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/app/installations":
+			_, _ = w.Write([]byte(`[{"id": 42}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/app/installations/42/access_tokens":
+			n := minted.Add(1)
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, n, time.Now().Add(expiresIn).Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// verifyJWT checks the signature and issuer of an app JWT.
+func verifyJWT(key *rsa.PublicKey, authorization, issuer string) error {
+	jwt, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return fmt.Errorf("no bearer token in %q", authorization)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed jwt %q", jwt)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+
+	var claims struct {
+		Issuer    string `json:"iss"`
+		IssuedAt  int64  `json:"iat"`
+		ExpiresAt int64  `json:"exp"`
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return err
+	}
+
+	if claims.Issuer != issuer {
+		return fmt.Errorf("issuer %q, want %q", claims.Issuer, issuer)
+	}
+
+	if claims.ExpiresAt-claims.IssuedAt > 600 {
+		return fmt.Errorf("jwt is valid for more than ten minutes")
+	}
+
+	return nil
+}
+
+func newAppKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	return key, string(keyPEM)
+}
+
+func TestAppTokenSource(t *testing.T) {
+	key, keyPEM := newAppKey(t)
+
+	var minted atomic.Int32
+
+	server := appServer(t, key, time.Hour, &minted)
+	baseURL := server.URL + "/"
+
+	// The installation is looked up, as the app is installed once.
+	source, err := newAppTokenSource(AppConfig{ID: 123, PrivateKey: keyPEM}, &baseURL)
+	require.NoError(t, err)
+
+	reuse := oauth2.ReuseTokenSourceWithExpiry(nil, source, tokenRefreshMargin)
+
+	token, err := reuse.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_1", token.AccessToken)
+	assert.Equal(t, int64(42), source.installationID)
+
+	// Valid tokens are reused.
+	token, err = reuse.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_1", token.AccessToken)
+	assert.Equal(t, int32(1), minted.Load())
+}
+
+func TestAppTokenSource_Refresh(t *testing.T) {
+	key, keyPEM := newAppKey(t)
+
+	var minted atomic.Int32
+
+	// Tokens that expire within the refresh margin are replaced.
+	server := appServer(t, key, tokenRefreshMargin/2, &minted)
+	baseURL := server.URL + "/"
+
+	source, err := newAppTokenSource(AppConfig{ID: 123, InstallationID: 42, PrivateKey: keyPEM}, &baseURL)
+	require.NoError(t, err)
+
+	reuse := oauth2.ReuseTokenSourceWithExpiry(nil, source, tokenRefreshMargin)
+
+	for _, want := range []string{"ghs_1", "ghs_2"} {
+		token, err := reuse.Token()
+		require.NoError(t, err)
+		assert.Equal(t, want, token.AccessToken)
+	}
+}
+
+func TestNewTokenSource(t *testing.T) {
+	source, err := NewTokenSource(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, source)
+
+	source, err = NewTokenSource(Config{Token: "ghp_x"})
+	require.NoError(t, err)
+
+	token, err := source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_x", token.AccessToken)
+
+	_, err = NewTokenSource(Config{App: AppConfig{ID: 1, PrivateKey: "not a key"}})
+	require.ErrorContains(t, err, "not PEM encoded")
+}
+
+// rotatingTokenSource returns a new token on every call.
+type rotatingTokenSource struct {
+	minted atomic.Int32
+}
+
+func (s *rotatingTokenSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: fmt.Sprintf("ghs_%d", s.minted.Add(1))}, nil
+}
+
+func TestNewHTTPClient_CacheSurvivesTokenRotation(t *testing.T) {
+	var (
+		authorizations []string
+		notModified    atomic.Int32
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("v1"))
+	}))
+	defer server.Close()
+
+	cache, err := httpcache.New(httpcache.Config{})
+	require.NoError(t, err)
+
+	auth := &credentialTokenSource{TokenSource: &rotatingTokenSource{}, credential: "app/1/installation/2"}
+	client := NewHTTPClient(auth, cache, nil)
+
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// Each request had a new token, yet the second was answered with a 304.
+	assert.Equal(t, []string{"Bearer ghs_1", "Bearer ghs_2"}, authorizations)
+	assert.Equal(t, int32(1), notModified.Load())
+}
+
+// recordingTransport records the Authorization header of requests.
+type recordingTransport struct {
+	authorization map[string]string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.authorization[req.URL.Host] = req.Header.Get("Authorization")
+
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestGitHubHostTransport(t *testing.T) {
+	base := &recordingTransport{authorization: make(map[string]string)}
+	transport := &githubHostTransport{
+		authenticated: &oauth2.Transport{Source: StaticTokenSource("ghp_x"), Base: base},
+		base:          base,
+	}
+
+	for _, url := range []string{
+		"https://raw.githubusercontent.com/ethpandaops/devnets/main/inventory.ini",
+		"https://example.com/inventory.ini",
+	} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	assert.Equal(t, "Bearer ghp_x", base.authorization["raw.githubusercontent.com"])
+	assert.Empty(t, base.authorization["example.com"])
+}
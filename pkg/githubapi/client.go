@@ -0,0 +1,155 @@
+// Package githubapi builds the GitHub API clients shared by the discovery
+// providers and generators, instrumenting every call with metrics.
+package githubapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	gh "github.com/google/go-github/v53/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/oauth2"
+
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
+)
+
+var (
+	apiCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "github",
+		Name:      "api_calls_total",
+		Help:      "GitHub API calls by rate-limit resource and response status code.",
+	}, []string{"resource", "code"})
+
+	rateLimitRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "github",
+		Name:      "rate_limit_remaining",
+		Help:      "Remaining GitHub API requests in the current rate-limit window.",
+	}, []string{"resource"})
+
+	rateLimitReset = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "github",
+		Name:      "rate_limit_reset_timestamp_seconds",
+		Help:      "Unix time at which the GitHub API rate-limit window resets.",
+	}, []string{"resource"})
+
+	rateLimitBackoffs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "github",
+		Name:      "rate_limit_backoffs_total",
+		Help:      "GitHub API requests retried after a rate-limited response.",
+	}, []string{"resource"})
+)
+
+// NewClient returns a GitHub client. If auth is nil, an unauthenticated client
+// is used (subject to stricter rate limits). Responses are revalidated through
+// cache and rate limits are tracked by rateLimits; both may be nil.
+func NewClient(auth oauth2.TokenSource, cache *httpcache.Cache, rateLimits *ratelimit.Tracker) *gh.Client {
+	return gh.NewClient(NewHTTPClient(auth, cache, rateLimits))
+}
+
+// NewHTTPClient returns an instrumented HTTP client for the GitHub API,
+// authenticated by auth, caching responses in cache and backing off on rate
+// limits tracked by rateLimits if they are not nil.
+func NewHTTPClient(auth oauth2.TokenSource, cache *httpcache.Cache, rateLimits *ratelimit.Tracker) *http.Client {
+	// The cache sits between the credentials, which are part of its key, and
+	// the instrumentation, so 304 responses are still counted as API calls.
+	transport := cacheTransport(cache, &Transport{RateLimits: rateLimits}, auth)
+
+	if auth != nil {
+		transport = &oauth2.Transport{
+			Source: auth,
+			Base:   transport,
+		}
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// Transport counts GitHub API calls and records the rate-limit headers of
+// every response.
+type Transport struct {
+	// Base is the underlying transport. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+
+	// RateLimits, if set, tracks the rate limits of responses and decides when
+	// rate-limited requests are retried.
+	RateLimits *ratelimit.Tracker
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if err != nil {
+			apiCalls.WithLabelValues("unknown", "error").Inc()
+
+			return nil, err
+		}
+
+		resource := observe(resp)
+		t.RateLimits.Observe(resp.Header)
+
+		// Requests with a body can only be retried if it can be read again.
+		wait, retry := t.RateLimits.Backoff(resp, attempt)
+		if !retry || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		_ = resp.Body.Close()
+
+		rateLimitBackoffs.WithLabelValues(resource).Inc()
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// observe records the metrics of a response and returns its rate-limit
+// resource.
+func observe(resp *http.Response) string {
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "unknown"
+	}
+
+	apiCalls.WithLabelValues(resource, strconv.Itoa(resp.StatusCode)).Inc()
+
+	if remaining, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Remaining"), 64); err == nil {
+		rateLimitRemaining.WithLabelValues(resource).Set(remaining)
+	}
+
+	if reset, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Reset"), 64); err == nil {
+		rateLimitReset.WithLabelValues(resource).Set(reset)
+	}
+
+	return resource
+}
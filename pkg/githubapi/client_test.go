@@ -0,0 +1,131 @@
+package githubapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
+)
+
+func TestTransport_RecordsRateLimit(t *testing.T) {
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPClient(StaticTokenSource("test-token"), nil, nil).Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "Bearer test-token", authorization)
+
+	assert.InDelta(t, 1, testutil.ToFloat64(apiCalls.WithLabelValues("core", "200")), 0)
+	assert.InDelta(t, 4321, testutil.ToFloat64(rateLimitRemaining.WithLabelValues("core")), 0)
+	assert.InDelta(t, 1700000000, testutil.ToFloat64(rateLimitReset.WithLabelValues("core")), 0)
+}
+
+func TestNewHTTPClient_Unauthenticated(t *testing.T) {
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPClient(nil, nil, nil).Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Empty(t, authorization)
+}
+
+func TestNewHTTPClient_Cache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("v1"))
+	}))
+	defer server.Close()
+
+	cache, err := httpcache.New(httpcache.Config{})
+	require.NoError(t, err)
+
+	client := NewHTTPClient(StaticTokenSource("test-token"), cache, nil)
+
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "v1", string(body))
+	}
+
+	// The revalidation is still counted as an API call.
+	assert.InDelta(t, 1, testutil.ToFloat64(apiCalls.WithLabelValues("graphql", "200")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(apiCalls.WithLabelValues("graphql", "304")), 0)
+}
+
+func TestTransport_RetriesRateLimited(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Resource", "code_search")
+		w.Header().Set("X-RateLimit-Remaining", "9")
+
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	rateLimits, err := ratelimit.New(ratelimit.Config{})
+	require.NoError(t, err)
+
+	resp, err := NewHTTPClient(nil, nil, rateLimits).Get(server.URL)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), requests.Load())
+
+	assert.InDelta(t, 1, testutil.ToFloat64(rateLimitBackoffs.WithLabelValues("code_search")), 0)
+
+	limits := rateLimits.Limits()
+	require.Len(t, limits, 1)
+	assert.Equal(t, 9, limits[0].Remaining)
+}
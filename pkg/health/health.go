@@ -0,0 +1,225 @@
+// Package health reports liveness and readiness of the continuous discovery
+// loop, based on when discovery and uploads last succeeded.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// Config represents the configuration for health checks.
+type Config struct {
+	// MaxDiscoveryAge is how long ago the last discovery run may have
+	// completed. Older runs fail both liveness and readiness, as the discovery
+	// loop is presumed stuck.
+	MaxDiscoveryAge time.Duration `mapstructure:"maxDiscoveryAge"`
+
+	// MaxUploadAge is how long ago the last successful upload may have been.
+	// Older uploads fail readiness.
+	MaxUploadAge time.Duration `mapstructure:"maxUploadAge"`
+}
+
+// SetDefaults applies default values relative to the discovery interval.
+func (c *Config) SetDefaults(interval time.Duration) {
+	if c.MaxDiscoveryAge == 0 {
+		c.MaxDiscoveryAge = 3 * interval
+	}
+
+	if c.MaxUploadAge == 0 {
+		c.MaxUploadAge = 3 * interval
+	}
+}
+
+// Status is the body of the health endpoints.
+type Status struct {
+	Status          string                   `json:"status"`
+	Failures        []string                 `json:"failures,omitempty"`
+	StartedAt       time.Time                `json:"startedAt"`
+	LastDiscovery   *time.Time               `json:"lastDiscovery,omitempty"`
+	LastUpload      *time.Time               `json:"lastUpload,omitempty"`
+	LastUploadError string                   `json:"lastUploadError,omitempty"`
+	Providers       []discovery.ProviderInfo `json:"providers,omitempty"`
+}
+
+// Checker tracks the state of the discovery loop.
+type Checker struct {
+	config          Config
+	now             func() time.Time
+	mutex           sync.RWMutex
+	startedAt       time.Time
+	lastDiscovery   time.Time
+	lastUpload      time.Time
+	lastUploadError string
+	providers       []discovery.ProviderInfo
+}
+
+// NewChecker creates a new checker. Staleness is measured from now until the
+// first discovery and upload complete.
+func NewChecker(config Config) *Checker {
+	return &Checker{
+		config:    config,
+		now:       time.Now,
+		startedAt: time.Now(),
+	}
+}
+
+// RecordDiscovery records a completed discovery run.
+func (c *Checker) RecordDiscovery(result discovery.Result) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lastDiscovery = c.now()
+	c.providers = result.Providers
+}
+
+// RecordUpload records the outcome of an upload.
+func (c *Checker) RecordUpload(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err != nil {
+		c.lastUploadError = err.Error()
+
+		return
+	}
+
+	c.lastUpload = c.now()
+	c.lastUploadError = ""
+}
+
+// Liveness reports whether the discovery loop is still making progress.
+func (c *Checker) Liveness() Status {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	status := c.status()
+	status.Failures = c.checkDiscovery()
+
+	return finalize(status)
+}
+
+// Readiness reports whether a discovery has completed and discovery and
+// uploads are recent enough.
+func (c *Checker) Readiness() Status {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	status := c.status()
+
+	if c.lastDiscovery.IsZero() {
+		status.Failures = append(status.Failures, "waiting for the first discovery to complete")
+	}
+
+	status.Failures = append(status.Failures, c.checkDiscovery()...)
+	status.Failures = append(status.Failures, c.checkUpload()...)
+
+	return finalize(status)
+}
+
+// LivenessHandler serves Liveness, with 503 when unhealthy.
+func (c *Checker) LivenessHandler() http.Handler {
+	return statusHandler(c.Liveness)
+}
+
+// ReadinessHandler serves Readiness, with 503 when not ready.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return statusHandler(c.Readiness)
+}
+
+// Routes returns the health endpoints by pattern, to be served on every
+// listener of the service.
+func (c *Checker) Routes() map[string]http.Handler {
+	return map[string]http.Handler{
+		"GET /healthz": c.LivenessHandler(),
+		"GET /readyz":  c.ReadinessHandler(),
+	}
+}
+
+// status returns the recorded state. Callers must hold the mutex.
+func (c *Checker) status() Status {
+	status := Status{
+		StartedAt:       c.startedAt,
+		LastUploadError: c.lastUploadError,
+		Providers:       c.providers,
+	}
+
+	if !c.lastDiscovery.IsZero() {
+		lastDiscovery := c.lastDiscovery
+		status.LastDiscovery = &lastDiscovery
+	}
+
+	if !c.lastUpload.IsZero() {
+		lastUpload := c.lastUpload
+		status.LastUpload = &lastUpload
+	}
+
+	return status
+}
+
+// checkDiscovery returns a failure if discovery hasn't completed recently.
+func (c *Checker) checkDiscovery() []string {
+	if age, ok := c.checkAge(c.lastDiscovery, c.config.MaxDiscoveryAge); !ok {
+		return []string{fmt.Sprintf("no discovery completed in %s (max %s)", age, c.config.MaxDiscoveryAge)}
+	}
+
+	return nil
+}
+
+// checkUpload returns a failure if no upload succeeded recently.
+func (c *Checker) checkUpload() []string {
+	age, ok := c.checkAge(c.lastUpload, c.config.MaxUploadAge)
+	if ok {
+		return nil
+	}
+
+	failure := fmt.Sprintf("no successful upload in %s (max %s)", age, c.config.MaxUploadAge)
+	if c.lastUploadError != "" {
+		failure += ": " + c.lastUploadError
+	}
+
+	return []string{failure}
+}
+
+// checkAge returns the age of last, measured from startup if it is zero, and
+// whether it is within max.
+func (c *Checker) checkAge(last time.Time, maxAge time.Duration) (time.Duration, bool) {
+	if last.IsZero() {
+		last = c.startedAt
+	}
+
+	age := c.now().Sub(last).Round(time.Second)
+
+	return age, age <= maxAge
+}
+
+// finalize sets the overall status from the failures.
+func finalize(status Status) Status {
+	status.Status = "ok"
+	if len(status.Failures) > 0 {
+		status.Status = "unhealthy"
+	}
+
+	return status
+}
+
+// statusHandler serves a status as JSON.
+func statusHandler(check func() Status) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		status := check()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if len(status.Failures) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		_ = json.NewEncoder(w).Encode(status)
+	})
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// newTestChecker returns a checker with a controllable clock.
+func newTestChecker(t *testing.T) (*Checker, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+
+	config := Config{}
+	config.SetDefaults(time.Hour)
+
+	checker := NewChecker(config)
+	checker.startedAt = now
+	checker.now = func() time.Time { return now }
+
+	return checker, &now
+}
+
+func TestChecker_Startup(t *testing.T) {
+	checker, now := newTestChecker(t)
+
+	assert.Equal(t, "ok", checker.Liveness().Status)
+
+	readiness := checker.Readiness()
+	assert.Equal(t, "unhealthy", readiness.Status)
+	assert.Equal(t, []string{"waiting for the first discovery to complete"}, readiness.Failures)
+
+	// A loop that never completes a discovery is eventually not alive.
+	*now = now.Add(4 * time.Hour)
+
+	liveness := checker.Liveness()
+	assert.Equal(t, "unhealthy", liveness.Status)
+	assert.Equal(t, []string{"no discovery completed in 4h0m0s (max 3h0m0s)"}, liveness.Failures)
+}
+
+func TestChecker_DiscoveryAndUpload(t *testing.T) {
+	checker, now := newTestChecker(t)
+
+	providers := []discovery.ProviderInfo{{Name: "github", Status: discovery.ProviderStatusFailed, Error: "rate limited"}}
+
+	checker.RecordDiscovery(discovery.Result{Providers: providers})
+	checker.RecordUpload(nil)
+
+	readiness := checker.Readiness()
+	assert.Equal(t, "ok", readiness.Status)
+	assert.Equal(t, providers, readiness.Providers)
+	require.NotNil(t, readiness.LastUpload)
+
+	// Discovery keeps running but uploads keep failing.
+	*now = now.Add(4 * time.Hour)
+
+	checker.RecordDiscovery(discovery.Result{})
+	checker.RecordUpload(errors.New("access denied"))
+
+	assert.Equal(t, "ok", checker.Liveness().Status)
+
+	readiness = checker.Readiness()
+	assert.Equal(t, "unhealthy", readiness.Status)
+	assert.Equal(t, []string{"no successful upload in 4h0m0s (max 3h0m0s): access denied"}, readiness.Failures)
+	assert.Equal(t, "access denied", readiness.LastUploadError)
+}
+
+func TestHandlers(t *testing.T) {
+	checker, _ := newTestChecker(t)
+
+	rec := httptest.NewRecorder()
+	checker.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var status Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "unhealthy", status.Status)
+}
+
+func TestRoutes(t *testing.T) {
+	checker, _ := newTestChecker(t)
+
+	mux := http.NewServeMux()
+	for pattern, handler := range checker.Routes() {
+		mux.Handle(pattern, handler)
+	}
+
+	for path, code := range map[string]int{
+		"/healthz": http.StatusOK,
+		"/readyz":  http.StatusServiceUnavailable,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, rec.Code, path)
+	}
+}
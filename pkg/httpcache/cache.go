@@ -0,0 +1,210 @@
+// Package httpcache is a shared HTTP caching layer for GitHub API and raw
+// content fetches. It stores the ETag and Last-Modified of responses, in memory
+// and optionally on disk, and revalidates them with conditional requests: a
+// 304 Not Modified is answered from the cache and, for the GitHub API, doesn't
+// count against the rate limit.
+package httpcache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultMaxEntries is the default number of responses kept in memory.
+const DefaultMaxEntries = 5000
+
+// Config represents the configuration for the HTTP cache.
+type Config struct {
+	// Enabled turns the cache on. Defaults to true.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Dir persists cached responses on disk, so they survive restarts and
+	// one-shot runs. Responses are only kept in memory if empty.
+	Dir string `mapstructure:"dir"`
+
+	// MaxEntries is the number of responses kept in memory, the least recently
+	// used are evicted first. Responses on disk are not evicted.
+	MaxEntries int `mapstructure:"maxEntries"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	if c.Enabled == nil {
+		enabled := true
+		c.Enabled = &enabled
+	}
+
+	if c.MaxEntries == 0 {
+		c.MaxEntries = DefaultMaxEntries
+	}
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("maxEntries must not be negative, got %d", c.MaxEntries)
+	}
+
+	return nil
+}
+
+// entry is a cached response.
+type entry struct {
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// Cache stores responses by request. A nil or disabled Cache caches nothing.
+type Cache struct {
+	config Config
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// lruItem is an element of Cache.lru.
+type lruItem struct {
+	key   string
+	entry *entry
+}
+
+// New creates a new cache.
+func New(config Config) (*Cache, error) {
+	config.SetDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid http cache config: %w", err)
+	}
+
+	if *config.Enabled && config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create http cache dir: %w", err)
+		}
+	}
+
+	return &Cache{
+		config:  config,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+// enabled returns true if the cache stores responses.
+func (c *Cache) enabled() bool {
+	return c != nil && *c.config.Enabled
+}
+
+// get returns the cached response for key, loading it from disk if it isn't
+// in memory.
+func (c *Cache) get(key string) (*entry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(element)
+
+		return element.Value.(*lruItem).entry, true //nolint:forcetypeassert // Only lruItems are stored.
+	}
+
+	if c.config.Dir == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+
+	c.remember(key, &e)
+
+	return &e, true
+}
+
+// set stores the response for key.
+func (c *Cache) set(key string, e *entry) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruItem).entry = e //nolint:forcetypeassert // Only lruItems are stored.
+		c.lru.MoveToFront(element)
+	} else {
+		c.remember(key, e)
+	}
+
+	if c.config.Dir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached response: %w", err)
+	}
+
+	// Write to a temporary file first, so a crash can't leave a torn entry.
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		return errors.Join(fmt.Errorf("failed to write cached response: %w", err), os.Remove(tmp))
+	}
+
+	return nil
+}
+
+// remember adds an entry to memory, evicting the least recently used if full.
+// The caller must hold the mutex.
+func (c *Cache) remember(key string, e *entry) {
+	c.entries[key] = c.lru.PushFront(&lruItem{key: key, entry: e})
+
+	for c.lru.Len() > c.config.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key) //nolint:forcetypeassert // Only lruItems are stored.
+	}
+}
+
+// path returns the file of key in the cache dir.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.config.Dir, key+".json")
+}
+
+// requestKey identifies the cached response of a request. Responses vary by
+// media type and credentials, so the Accept header and credential are part of
+// the key. Without a credential, the Authorization header is used. The key is
+// hashed so tokens never end up on disk.
+func requestKey(req *http.Request, credential string) string {
+	h := sha256.New()
+
+	// The prefixes keep a credential from colliding with an Authorization
+	// header.
+	identity := "authorization:" + req.Header.Get("Authorization")
+	if credential != "" {
+		identity = "credential:" + credential
+	}
+
+	for _, part := range []string{req.Method, req.URL.String(), req.Header.Get("Accept"), identity} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
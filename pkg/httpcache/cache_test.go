@@ -0,0 +1,239 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// etagServer serves body with an ETag and answers matching conditional
+// requests with 304.
+func etagServer(t *testing.T, body *atomic.Value, notModified *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := body.Load().(string)
+		etag := `"` + content + `"`
+
+		w.Header().Set("X-RateLimit-Remaining", "100")
+
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.Header().Set("X-RateLimit-Remaining", "99")
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(body)
+}
+
+func TestTransport_Revalidates(t *testing.T) {
+	var (
+		body        atomic.Value
+		notModified atomic.Int32
+	)
+
+	body.Store("v1")
+	server := etagServer(t, &body, &notModified)
+
+	cache, err := New(Config{})
+	require.NoError(t, err)
+
+	client := cache.Client(time.Second)
+
+	_, content := get(t, client, server.URL)
+	assert.Equal(t, "v1", content)
+	assert.Equal(t, int32(0), notModified.Load())
+
+	// Unchanged: answered from the cache, with the headers of the 304.
+	resp, content := get(t, client, server.URL)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "v1", content)
+	assert.Equal(t, "99", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, int32(1), notModified.Load())
+
+	// Changed: fetched again.
+	body.Store("v2")
+
+	_, content = get(t, client, server.URL)
+	assert.Equal(t, "v2", content)
+	assert.Equal(t, int32(1), notModified.Load())
+}
+
+func TestTransport_Disk(t *testing.T) {
+	var (
+		body        atomic.Value
+		notModified atomic.Int32
+	)
+
+	body.Store("persisted")
+	server := etagServer(t, &body, &notModified)
+	dir := t.TempDir()
+
+	first, err := New(Config{Dir: dir})
+	require.NoError(t, err)
+
+	get(t, first.Client(time.Second), server.URL)
+
+	// A new cache, as after a restart, revalidates the response on disk.
+	second, err := New(Config{Dir: dir})
+	require.NoError(t, err)
+
+	_, content := get(t, second.Client(time.Second), server.URL)
+	assert.Equal(t, "persisted", content)
+	assert.Equal(t, int32(1), notModified.Load())
+}
+
+func TestTransport_KeyIncludesCredentials(t *testing.T) {
+	var (
+		body        atomic.Value
+		notModified atomic.Int32
+	)
+
+	body.Store("private")
+	server := etagServer(t, &body, &notModified)
+
+	cache, err := New(Config{})
+	require.NoError(t, err)
+
+	client := cache.Client(time.Second)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer a")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	// Another token doesn't reuse the cached response.
+	req.Header.Set("Authorization", "Bearer b")
+
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, int32(0), notModified.Load())
+}
+
+func TestTransport_CredentialSurvivesTokenRotation(t *testing.T) {
+	var (
+		body        atomic.Value
+		notModified atomic.Int32
+	)
+
+	body.Store("private")
+	server := etagServer(t, &body, &notModified)
+
+	cache, err := New(Config{})
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: cache.CredentialTransport(nil, "app/1/installation/2")}
+
+	for _, token := range []string{"Bearer ghs_1", "Bearer ghs_2"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", token)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// The refreshed token revalidates the response cached with the old one.
+	assert.Equal(t, int32(1), notModified.Load())
+
+	// Another credential doesn't reuse the cached response.
+	other := &http.Client{Transport: cache.CredentialTransport(nil, "app/1/installation/3")}
+
+	get(t, other, server.URL)
+	assert.Equal(t, int32(1), notModified.Load())
+}
+
+func TestTransport_Bypass(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		// Already conditional requests see the server's 304 themselves.
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"x"`)
+		_, _ = w.Write([]byte("x"))
+	}))
+	defer server.Close()
+
+	cache, err := New(Config{})
+	require.NoError(t, err)
+
+	client := cache.Client(time.Second)
+	get(t, client, server.URL)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", `"x"`)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+}
+
+func TestCache_Eviction(t *testing.T) {
+	cache, err := New(Config{MaxEntries: 2})
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, cache.set(key, &entry{Status: http.StatusOK}))
+	}
+
+	_, ok := cache.get("a")
+	assert.False(t, ok, "least recently used entry is evicted")
+
+	_, ok = cache.get("c")
+	assert.True(t, ok)
+}
+
+func TestCache_Disabled(t *testing.T) {
+	disabled := false
+
+	cache, err := New(Config{Enabled: &disabled})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.DefaultTransport, cache.Transport(nil))
+
+	var nilCache *Cache
+	assert.Equal(t, http.DefaultTransport, nilCache.Transport(nil))
+}
@@ -0,0 +1,24 @@
+package httpcache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+)
+
+var (
+	requests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "httpcache",
+		Name:      "requests_total",
+		Help:      "Requests through the HTTP cache by result (hit, miss or bypass). Hits were answered with 304 Not Modified.",
+	}, []string{"result"})
+
+	writeErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "httpcache",
+		Name:      "write_errors_total",
+		Help:      "Responses that could not be written to the cache dir.",
+	})
+)
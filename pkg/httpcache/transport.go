@@ -0,0 +1,167 @@
+package httpcache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transport returns a transport that revalidates cached responses with
+// conditional requests before sending them through base, which defaults to
+// http.DefaultTransport. A nil or disabled cache returns base unchanged.
+func (c *Cache) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if !c.enabled() {
+		return base
+	}
+
+	return &transport{cache: c, base: base}
+}
+
+// CredentialTransport is Transport for requests authenticated by a single
+// credential whose tokens may rotate, such as a GitHub App installation.
+// Responses are cached under credential rather than the Authorization header,
+// so they are still revalidated after the token is refreshed.
+func (c *Cache) CredentialTransport(base http.RoundTripper, credential string) http.RoundTripper {
+	t := c.Transport(base)
+
+	if cached, ok := t.(*transport); ok {
+		cached.credential = credential
+	}
+
+	return t
+}
+
+// Client returns an HTTP client with the given timeout that uses the cache.
+func (c *Cache) Client(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: c.Transport(nil),
+	}
+}
+
+// transport is the caching http.RoundTripper.
+type transport struct {
+	cache *Cache
+	base  http.RoundTripper
+	// credential, if set, replaces the Authorization header in cache keys.
+	credential string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Only plain GETs are cached. Requests that are already conditional, such
+	// as GitHub's commit SHA lookups, handle 304s themselves.
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		requests.WithLabelValues("bypass").Inc()
+
+		return t.base.RoundTrip(req)
+	}
+
+	key := requestKey(req, t.credential)
+
+	cached, ok := t.cache.get(key)
+	if ok {
+		req = req.Clone(req.Context())
+
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		requests.WithLabelValues("hit").Inc()
+
+		return cachedResponse(req, cached, resp.Header), nil
+	}
+
+	if !cacheable(resp) {
+		requests.WithLabelValues("miss").Inc()
+
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	requests.WithLabelValues("miss").Inc()
+
+	if err := t.cache.set(key, &entry{
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header.Clone(),
+		Body:   body,
+	}); err != nil {
+		// The response is still good, it just won't be revalidated next time.
+		writeErrors.Inc()
+	}
+
+	return resp, nil
+}
+
+// cacheable returns true if resp can be revalidated later.
+func cacheable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// cachedResponse builds the response to req from a cached entry. Headers of
+// the 304 response, such as GitHub's rate-limit headers, replace the cached
+// ones.
+func cachedResponse(req *http.Request, cached *entry, notModified http.Header) *http.Response {
+	header := cached.Header.Clone()
+
+	for name, values := range notModified {
+		if name == "Content-Length" {
+			continue
+		}
+
+		header[name] = values
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(cached.Body)))
+
+	return &http.Response{
+		Status:        strconv.Itoa(cached.Status) + " " + http.StatusText(cached.Status),
+		StatusCode:    cached.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
 	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/semaphore"
@@ -105,6 +106,8 @@ func (s *Service) Run(ctx context.Context) error {
 	// Generate inventories for each network concurrently
 	inventories := s.generateInventories(ctx, activeNetworks)
 
+	metrics.ObserveGeneratedItems(metrics.GeneratorInventory, len(inventories), len(activeNetworks)-len(inventories))
+
 	// Upload inventory files to S3
 	if err := s.uploadInventories(ctx, inventories); err != nil {
 		return fmt.Errorf("failed to upload inventories: %w", err)
@@ -0,0 +1,201 @@
+// Package jsonschema generates JSON Schemas (draft 2020-12) from Go types and
+// validates JSON documents against them. It supports the subset of JSON Schema
+// needed to describe types encoded with encoding/json.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. Only the keywords used by Generate and understood
+// by Validate are supported.
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+
+	// never marks the schema that no value matches, encoded as false.
+	never bool
+}
+
+// False returns the schema that no value matches.
+func False() *Schema {
+	return &Schema{never: true}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.never {
+		return []byte("false"), nil
+	}
+
+	type plain Schema
+
+	return json.Marshal((*plain)(s))
+}
+
+// Types is the "type" keyword. It is encoded as a string for a single type and
+// as an array otherwise.
+type Types []string
+
+// MarshalJSON implements json.Marshaler.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+
+	return json.Marshal([]string(t))
+}
+
+// Generate returns a schema for the type of v. Named struct types are placed
+// in $defs and referenced by name.
+func Generate(v any) *Schema {
+	g := &generator{defs: make(map[string]*Schema)}
+
+	schema := g.schemaFor(reflect.TypeOf(v))
+	schema.Draft = Draft
+	schema.Defs = g.defs
+
+	return schema
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// schemaFor returns the schema of a type as encoded by encoding/json.
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schemaFor(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+
+		return &Schema{Type: Types{"integer"}, Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate.
+			g.defs[t.Name()] = &Schema{}
+			*g.defs[t.Name()] = *g.structSchema(t)
+		}
+
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	default:
+		// Interfaces and other kinds may hold any value.
+		return &Schema{}
+	}
+}
+
+// structSchema returns the object schema of a struct's exported JSON fields.
+// Other properties are allowed, so documents with fields added by a newer
+// minor version still validate.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       Types{"object"},
+		Properties: make(map[string]*Schema),
+	}
+
+	for field := range t.Fields() {
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, ok := FieldName(field)
+		if !ok {
+			continue
+		}
+
+		property := g.schemaFor(field.Type)
+
+		// encoding/json encodes nil slices and maps as null.
+		if kind := field.Type.Kind(); kind == reflect.Slice || kind == reflect.Map {
+			property = nullable(property)
+		}
+
+		schema.Properties[name] = property
+
+		if !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// FieldName returns the JSON name of a struct field, whether it is omitted
+// when empty, and false if the field is not encoded.
+func FieldName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	omitEmpty := false
+
+	for option := range strings.SplitSeq(options, ",") {
+		if option == "omitempty" || option == "omitzero" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, true
+}
+
+// nullable allows null in addition to the schema's types.
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AnyOf: []*Schema{{Type: Types{"null"}}, schema}}
+	}
+
+	if len(schema.Type) > 0 && !slices.Contains(schema.Type, "null") {
+		schema.Type = append(schema.Type, "null")
+	}
+
+	return schema
+}
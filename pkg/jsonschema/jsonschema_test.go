@@ -0,0 +1,134 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+type testDoc struct {
+	ID       uint64              `json:"id"`
+	Ratio    float64             `json:"ratio"`
+	Label    string              `json:"label,omitempty"`
+	Created  time.Time           `json:"created"`
+	Items    []testItem          `json:"items"`
+	Tags     map[string]string   `json:"tags,omitempty"`
+	Parent   *testItem           `json:"parent,omitempty"`
+	Children map[string]*testDoc `json:"children,omitempty"`
+	Ignored  string              `json:"-"`
+	internal string
+}
+
+func TestGenerate(t *testing.T) {
+	schema := Generate(testDoc{})
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, Draft, decoded["$schema"])
+	assert.Equal(t, "#/$defs/testDoc", decoded["$ref"])
+
+	defs := decoded["$defs"].(map[string]any)
+	doc := defs["testDoc"].(map[string]any)
+
+	assert.Equal(t, []any{"id", "ratio", "created", "items"}, doc["required"])
+	assert.NotContains(t, doc, "additionalProperties")
+
+	properties := doc["properties"].(map[string]any)
+	assert.NotContains(t, properties, "Ignored")
+	assert.NotContains(t, properties, "internal")
+	assert.Equal(t, map[string]any{"type": "integer", "minimum": 0.0}, properties["id"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, properties["created"])
+	assert.Equal(t, []any{"array", "null"}, properties["items"].(map[string]any)["type"])
+	assert.Contains(t, defs, "testItem")
+}
+
+func TestValidate(t *testing.T) {
+	schema := Generate(testDoc{})
+
+	tests := []struct {
+		name   string
+		doc    string
+		errors []string
+	}{
+		{
+			name: "valid",
+			doc: `{"id": 1, "ratio": 0.5, "created": "2025-01-01T00:00:00Z", "items": [{"name": "a"}],
+				"parent": {"name": "p"}, "children": {"c": {"id": 2, "ratio": 1, "created": "2025-01-01T00:00:00Z", "items": null}}}`,
+		},
+		{
+			name:   "missing required",
+			doc:    `{"id": 1, "ratio": 1, "created": "2025-01-01T00:00:00Z"}`,
+			errors: []string{`$: missing required property "items"`},
+		},
+		{
+			name: "wrong types",
+			doc:  `{"id": -1, "ratio": "high", "created": "yesterday", "items": {"name": "a"}}`,
+			errors: []string{
+				"$.created: \"yesterday\" is not a date-time",
+				"$.id: -1 is less than the minimum 0",
+				"$.items: expected array or null, got object",
+				"$.ratio: expected number, got string",
+			},
+		},
+		{
+			name: "unknown property",
+			doc:  `{"id": 1, "ratio": 1, "created": "2025-01-01T00:00:00Z", "items": [], "extra": true}`,
+		},
+		{
+			name:   "fractional integer",
+			doc:    `{"id": 1.5, "ratio": 1, "created": "2025-01-01T00:00:00Z", "items": []}`,
+			errors: []string{"$.id: expected integer, got number"},
+		},
+		{
+			name:   "nested",
+			doc:    `{"id": 1, "ratio": 1, "created": "2025-01-01T00:00:00Z", "items": [{"name": 1}], "parent": null}`,
+			errors: []string{"$.items[0].name: expected string, got integer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.doc))
+			if len(tt.errors) == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var validationErr *ValidationError
+
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.errors, validationErr.Errors)
+		})
+	}
+}
+
+func TestValidate_AdditionalPropertiesFalse(t *testing.T) {
+	schema := &Schema{
+		Type:                 Types{"object"},
+		Properties:           map[string]*Schema{"id": {Type: Types{"integer"}}},
+		AdditionalProperties: False(),
+	}
+
+	assert.NoError(t, schema.Validate([]byte(`{"id": 1}`)))
+	assert.ErrorContains(t, schema.Validate([]byte(`{"id": 1, "extra": true}`)), "$.extra: not allowed")
+}
+
+func TestValidate_Pattern(t *testing.T) {
+	schema := &Schema{Type: Types{"string"}, Pattern: `^1\.`}
+
+	assert.NoError(t, schema.Validate([]byte(`"1.2.0"`)))
+	assert.ErrorContains(t, schema.Validate([]byte(`"2.0.0"`)), `"2.0.0" does not match`)
+	assert.ErrorContains(t, schema.Validate([]byte(`{`)), "failed to parse document")
+}
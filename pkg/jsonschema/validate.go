@@ -0,0 +1,232 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxErrors is the number of violations reported by a ValidationError.
+const maxErrors = 20
+
+// ValidationError lists the paths at which a document violates a schema.
+type ValidationError struct {
+	Errors []string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("document does not match schema: %s", strings.Join(e.Errors, "; "))
+}
+
+// Validate validates a JSON document against the schema. It returns a
+// *ValidationError listing the violations.
+func (s *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	v := &validator{root: s}
+	v.validate(s, doc, "$")
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Errors: v.errors}
+	}
+
+	return nil
+}
+
+type validator struct {
+	root   *Schema
+	errors []string
+}
+
+// fail records a violation, keeping at most maxErrors.
+func (v *validator) fail(path, format string, args ...any) {
+	if len(v.errors) < maxErrors {
+		v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// validate checks a decoded value against a schema.
+func (v *validator) validate(schema *Schema, value any, path string) {
+	if schema.never {
+		v.fail(path, "not allowed")
+
+		return
+	}
+
+	if schema.Ref != "" {
+		ref, err := v.resolve(schema.Ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+
+			return
+		}
+
+		v.validate(ref, value, path)
+
+		return
+	}
+
+	if len(schema.AnyOf) > 0 {
+		v.validateAnyOf(schema.AnyOf, value, path)
+	}
+
+	if len(schema.Type) > 0 {
+		typ := typeOf(value)
+
+		// Every integer is also a number.
+		if !slices.Contains(schema.Type, typ) && (typ != "integer" || !slices.Contains(schema.Type, "number")) {
+			v.fail(path, "expected %s, got %s", strings.Join(schema.Type, " or "), typ)
+
+			return
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		v.validateString(schema, value, path)
+	case json.Number:
+		if schema.Minimum != nil {
+			if n, err := value.Float64(); err == nil && n < *schema.Minimum {
+				v.fail(path, "%s is less than the minimum %v", value, *schema.Minimum)
+			}
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, item := range value {
+				v.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case map[string]any:
+		v.validateObject(schema, value, path)
+	}
+}
+
+// validateAnyOf checks that a value matches at least one of the schemas.
+func (v *validator) validateAnyOf(schemas []*Schema, value any, path string) {
+	var closest []string
+
+	for _, schema := range schemas {
+		sub := &validator{root: v.root}
+		sub.validate(schema, value, path)
+
+		if len(sub.errors) == 0 {
+			return
+		}
+
+		// Prefer the violations of a schema that accepts the value's type, such
+		// as the object in an object-or-null.
+		if closest == nil || len(schema.Type) == 0 || slices.Contains(schema.Type, typeOf(value)) {
+			closest = sub.errors
+		}
+	}
+
+	for _, err := range closest {
+		if len(v.errors) < maxErrors {
+			v.errors = append(v.errors, err)
+		}
+	}
+}
+
+// validateString checks the string keywords.
+func (v *validator) validateString(schema *Schema, value, path string) {
+	if schema.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+			v.fail(path, "%q is not a date-time", value)
+		}
+	}
+
+	if schema.Pattern != "" {
+		re, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			v.fail(path, "invalid pattern %q: %v", schema.Pattern, err)
+
+			return
+		}
+
+		if !re.MatchString(value) {
+			v.fail(path, "%q does not match %q", value, schema.Pattern)
+		}
+	}
+}
+
+// validateObject checks the object keywords, visiting properties in sorted
+// order so errors are reported deterministically.
+func (v *validator) validateObject(schema *Schema, value map[string]any, path string) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.fail(path, "missing required property %q", name)
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propertyPath := path + "." + key
+
+		if property, ok := schema.Properties[key]; ok {
+			v.validate(property, value[key], propertyPath)
+
+			continue
+		}
+
+		if schema.AdditionalProperties != nil {
+			v.validate(schema.AdditionalProperties, value[key], propertyPath)
+		}
+	}
+}
+
+// resolve returns the schema a local $ref points to.
+func (v *validator) resolve(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+
+	schema, ok := v.root.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown $ref %q", ref)
+	}
+
+	return schema, nil
+}
+
+// typeOf returns the JSON Schema type of a decoded value.
+func typeOf(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil || !strings.ContainsAny(value.String(), ".eE") {
+			return "integer"
+		}
+
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Generator names used as the generator label.
+const (
+	GeneratorInventory             = "inventory"
+	GeneratorValidatorRanges       = "validator_ranges"
+	GeneratorEIP7870ReferenceNodes = "eip7870_reference_nodes"
+)
+
+var (
+	generatorRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "generator",
+		Name:      "runs_total",
+		Help:      "Generator runs by generator and status (success or failure).",
+	}, []string{"generator", "status"})
+
+	generatorDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "generator",
+		Name:      "duration_seconds",
+		Help:      "Duration of the last generator run.",
+	}, []string{"generator"})
+
+	generatorLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "generator",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful generator run.",
+	}, []string{"generator"})
+
+	generatorItems = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "generator",
+		Name:      "items",
+		Help:      "Items (networks or clients) processed by the last generator run, by outcome (success or failure).",
+	}, []string{"generator", "outcome"})
+)
+
+// ObserveGeneration records the outcome of a generator run that started at start.
+func ObserveGeneration(generator string, start time.Time, err error) {
+	generatorDuration.WithLabelValues(generator).Set(time.Since(start).Seconds())
+
+	if err != nil {
+		generatorRuns.WithLabelValues(generator, "failure").Inc()
+
+		return
+	}
+
+	generatorRuns.WithLabelValues(generator, "success").Inc()
+	generatorLastSuccess.WithLabelValues(generator).Set(float64(time.Now().Unix()))
+}
+
+// ObserveGeneratedItems records how many items a generator run processed.
+func ObserveGeneratedItems(generator string, succeeded, failed int) {
+	generatorItems.WithLabelValues(generator, "success").Set(float64(succeeded))
+	generatorItems.WithLabelValues(generator, "failure").Set(float64(failed))
+}
@@ -0,0 +1,10 @@
+// Package metrics serves and pushes the Prometheus metrics that the other
+// packages register with the default client_golang registry.
+package metrics
+
+// Namespace prefixes every metric name.
+const Namespace = "cartographoor"
+
+// DefBuckets are the default histogram buckets, in seconds. They extend
+// prometheus.DefBuckets to cover discovery runs and uploads of several minutes.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300}
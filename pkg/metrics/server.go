@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/sirupsen/logrus"
+)
+
+// Config represents the configuration for exporting metrics.
+type Config struct {
+	// Enabled serves metrics on ListenAddr in continuous mode.
+	Enabled    bool   `mapstructure:"enabled"`
+	ListenAddr string `mapstructure:"listenAddr"`
+
+	// PushgatewayURL pushes metrics to a Prometheus Pushgateway when a one-shot
+	// command (run --once, inventory, validator-ranges, eip7870-reference-nodes)
+	// completes, as those exit before they could be scraped.
+	PushgatewayURL string `mapstructure:"pushgatewayUrl"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	if c.ListenAddr == "" {
+		c.ListenAddr = ":9090"
+	}
+}
+
+// Handler returns an HTTP handler serving the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Server serves /metrics. Other handlers can be added before Start.
+type Server struct {
+	log    logrus.FieldLogger
+	config Config
+	mux    *http.ServeMux
+	server *http.Server
+}
+
+// NewServer creates a new metrics server.
+func NewServer(log logrus.FieldLogger, config Config) *Server {
+	config.SetDefaults()
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+
+	return &Server{
+		log:    log.WithField("module", "metrics"),
+		config: config,
+		mux:    mux,
+		server: &http.Server{
+			Addr:              config.ListenAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Handle registers an additional handler on the metrics listener.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start starts listening in the background.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.config.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.ListenAddr, err)
+	}
+
+	s.log.WithField("addr", listener.Addr().String()).Info("Starting metrics server")
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.WithError(err).Error("Metrics server failed")
+		}
+	}()
+
+	return nil
+}
+
+// Stop gracefully shuts down the server.
+func (s *Server) Stop(ctx context.Context) error {
+	s.log.Info("Stopping metrics server")
+
+	return s.server.Shutdown(ctx)
+}
+
+// Push replaces the metrics of job on a Prometheus Pushgateway with the
+// default registry.
+func Push(ctx context.Context, client *http.Client, gatewayURL, job string) error {
+	if err := push.New(gatewayURL, job).Gatherer(prometheus.DefaultGatherer).Client(client).PushContext(ctx); err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	ObserveGeneratedItems(GeneratorInventory, 3, 1)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `cartographoor_generator_items{generator="inventory",outcome="success"} 3`)
+}
+
+func TestPush(t *testing.T) {
+	ObserveGeneratedItems(GeneratorInventory, 3, 1)
+
+	var (
+		method, path string
+		body         []byte
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	require.NoError(t, Push(context.Background(), server.Client(), server.URL+"/", "cartographoor_run"))
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/cartographoor_run", path)
+	assert.NotEmpty(t, body)
+}
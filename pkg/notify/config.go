@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// Sink types.
+const (
+	SinkTypeWebhook = "webhook"
+	SinkTypeSlack   = "slack"
+	SinkTypeDiscord = "discord"
+)
+
+// Config represents the configuration for network lifecycle notifications.
+type Config struct {
+	Sinks []SinkConfig `mapstructure:"sinks"`
+}
+
+// SinkConfig represents a single notification destination.
+type SinkConfig struct {
+	Name string `mapstructure:"name"`
+	// Type is one of webhook, slack or discord.
+	Type string `mapstructure:"type"`
+	URL  string `mapstructure:"url"`
+	// Secret signs webhook payloads with HMAC-SHA256. Only used by webhook sinks.
+	Secret string `mapstructure:"secret"`
+	// Repositories limits the sink to networks of these repositories. Empty
+	// matches all networks, including static ones.
+	Repositories []string `mapstructure:"repositories"`
+	// Events limits the sink to these event types. Empty matches all events.
+	Events []string `mapstructure:"events"`
+}
+
+// Enabled returns true if any sink is configured.
+func (c *Config) Enabled() bool {
+	return len(c.Sinks) > 0
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	for i := range c.Sinks {
+		if c.Sinks[i].Type == "" {
+			c.Sinks[i].Type = SinkTypeWebhook
+		}
+
+		if c.Sinks[i].Name == "" {
+			c.Sinks[i].Name = fmt.Sprintf("%s-%d", c.Sinks[i].Type, i)
+		}
+	}
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	for _, sink := range c.Sinks {
+		if !slices.Contains([]string{SinkTypeWebhook, SinkTypeSlack, SinkTypeDiscord}, sink.Type) {
+			return fmt.Errorf("sink %s: unsupported type %q", sink.Name, sink.Type)
+		}
+
+		u, err := url.Parse(sink.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("sink %s: url must be an http or https URL", sink.Name)
+		}
+
+		for _, event := range sink.Events {
+			if !slices.Contains(EventTypes, event) {
+				return fmt.Errorf("sink %s: unsupported event type %q", sink.Name, event)
+			}
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if the sink wants the event.
+func (s *SinkConfig) matches(event Event) bool {
+	if len(s.Events) > 0 && !slices.Contains(s.Events, event.Type) {
+		return false
+	}
+
+	if len(s.Repositories) > 0 && !slices.Contains(s.Repositories, event.Repository) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethpandaops/cartographoor/pkg/changelog"
+)
+
+// Event types sent to sinks.
+const (
+	EventNetworkAdded    = "network_added"
+	EventNetworkArchived = "network_archived"
+	EventForkScheduled   = "fork_scheduled"
+)
+
+// EventTypes lists all supported event types.
+var EventTypes = []string{EventNetworkAdded, EventNetworkArchived, EventForkScheduled}
+
+// Event is a network lifecycle event.
+type Event struct {
+	Type       string    `json:"type"`
+	Network    string    `json:"network"`
+	Repository string    `json:"repository,omitempty"`
+	Fork       string    `json:"fork,omitempty"`
+	Activation string    `json:"activation,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// Message returns a human readable description of the event.
+func (e Event) Message() string {
+	network := e.Network
+	if e.Repository != "" {
+		network = fmt.Sprintf("%s (%s)", e.Network, e.Repository)
+	}
+
+	switch e.Type {
+	case EventNetworkAdded:
+		return fmt.Sprintf("New network %s", network)
+	case EventNetworkArchived:
+		return fmt.Sprintf("Network %s was archived", network)
+	case EventForkScheduled:
+		return fmt.Sprintf("Fork %s scheduled on %s at %s", e.Fork, network, e.Activation)
+	default:
+		return fmt.Sprintf("%s: %s", e.Type, network)
+	}
+}
+
+// EventsFromChangelog returns the lifecycle events contained in a changelog.
+// Networks that become inactive are reported as archived.
+func EventsFromChangelog(cl *changelog.Changelog) []Event {
+	events := make([]Event, 0)
+
+	for _, change := range cl.Changes {
+		event := Event{
+			Network:    change.Network,
+			Repository: change.Repository,
+			Time:       cl.To,
+		}
+
+		switch {
+		case change.Type == changelog.NetworkAdded:
+			event.Type = EventNetworkAdded
+		case change.Type == changelog.StatusChanged && change.To == "inactive":
+			event.Type = EventNetworkArchived
+		case change.Type == changelog.ForkScheduled:
+			event.Type = EventForkScheduled
+			event.Fork = change.Field
+			event.Activation = change.To
+		default:
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
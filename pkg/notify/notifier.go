@@ -0,0 +1,210 @@
+// Package notify sends network lifecycle events, such as new networks, archived
+// networks and newly scheduled forks, to webhook, Slack and Discord sinks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/changelog"
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of webhook payloads,
+// formatted as "sha256=<hex>".
+const SignatureHeader = "X-Cartographoor-Signature"
+
+// discordMaxContentLength is the maximum length of a Discord message, in
+// characters.
+const discordMaxContentLength = 2000
+
+// Notifier turns consecutive discovery results into lifecycle events and sends
+// them to the configured sinks.
+type Notifier struct {
+	log      logrus.FieldLogger
+	config   Config
+	client   *http.Client
+	previous *discovery.Result
+	mutex    sync.Mutex
+}
+
+// New creates a new notifier.
+func New(log logrus.FieldLogger, config Config, client *http.Client) (*Notifier, error) {
+	config.SetDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid notifications config: %w", err)
+	}
+
+	return &Notifier{
+		log:    log.WithField("module", "notify"),
+		config: config,
+		client: client,
+	}, nil
+}
+
+// SetPrevious sets the result that the next result is compared against, e.g.
+// the currently published networks on startup.
+func (n *Notifier) SetPrevious(result discovery.Result) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.previous = &result
+}
+
+// Notify compares the result with the previous one and sends the resulting
+// events. The first result only sets the baseline. Partial results are skipped
+// so that networks of a failed provider aren't reported as new once it
+// recovers; their changes are sent with the next complete result.
+func (n *Notifier) Notify(ctx context.Context, result discovery.Result) error {
+	n.mutex.Lock()
+
+	if result.Partial {
+		n.mutex.Unlock()
+		n.log.Debug("Skipping notifications for partial discovery result")
+
+		return nil
+	}
+
+	previous := n.previous
+	n.previous = &result
+
+	n.mutex.Unlock()
+
+	if previous == nil {
+		n.log.Debug("No previous discovery result, skipping notifications")
+
+		return nil
+	}
+
+	return n.Send(ctx, EventsFromChangelog(changelog.Diff(*previous, result)))
+}
+
+// Send sends the events to every sink whose filters match at least one event.
+func (n *Notifier) Send(ctx context.Context, events []Event) error {
+	var errs []error
+
+	for _, sink := range n.config.Sinks {
+		matched := make([]Event, 0, len(events))
+
+		for _, event := range events {
+			if sink.matches(event) {
+				matched = append(matched, event)
+			}
+		}
+
+		if len(matched) == 0 {
+			continue
+		}
+
+		if err := n.send(ctx, sink, matched); err != nil {
+			n.log.WithError(err).WithField("sink", sink.Name).Error("Failed to send notification")
+			errs = append(errs, fmt.Errorf("sink %s: %w", sink.Name, err))
+
+			continue
+		}
+
+		n.log.WithFields(logrus.Fields{
+			"sink":   sink.Name,
+			"events": len(matched),
+		}).Info("Sent notification")
+	}
+
+	return errors.Join(errs...)
+}
+
+// send posts the events to a single sink in the sink's payload format.
+func (n *Notifier) send(ctx context.Context, sink SinkConfig, events []Event) error {
+	body, err := buildPayload(sink.Type, events)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if sink.Type == SinkTypeWebhook && sink.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(sink.Secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// buildPayload encodes the events for the given sink type.
+func buildPayload(sinkType string, events []Event) ([]byte, error) {
+	var payload any
+
+	switch sinkType {
+	case SinkTypeSlack:
+		payload = map[string]string{"text": formatMessages(events)}
+	case SinkTypeDiscord:
+		payload = map[string]string{"content": truncate(formatMessages(events), discordMaxContentLength)}
+	default:
+		payload = map[string][]Event{"events": events}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", sinkType, err)
+	}
+
+	return body, nil
+}
+
+// truncate shortens s to at most limit characters, ending it with "..." if it
+// was cut. It never cuts a multi-byte character in half.
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	return string([]rune(s)[:limit-3]) + "..."
+}
+
+// formatMessages formats the events as one line per event.
+func formatMessages(events []Event) string {
+	lines := make([]string, 0, len(events))
+
+	for _, event := range events {
+		lines = append(lines, event.Message())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Sign returns the HMAC-SHA256 signature of a payload as sent in SignatureHeader.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,235 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/changelog"
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// recorder is a test HTTP server that records request bodies and headers.
+type recorder struct {
+	mutex    sync.Mutex
+	bodies   [][]byte
+	headers  []http.Header
+	server   *httptest.Server
+	response int
+}
+
+func newRecorder(t *testing.T) *recorder {
+	t.Helper()
+
+	r := &recorder{response: http.StatusOK}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		r.mutex.Lock()
+		r.bodies = append(r.bodies, body)
+		r.headers = append(r.headers, req.Header.Clone())
+		r.mutex.Unlock()
+
+		w.WriteHeader(r.response)
+	}))
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func newTestNotifier(t *testing.T, sinks ...SinkConfig) *Notifier {
+	t.Helper()
+
+	notifier, err := New(logrus.New(), Config{Sinks: sinks}, http.DefaultClient)
+	require.NoError(t, err)
+
+	return notifier
+}
+
+func TestNotify_Webhook(t *testing.T) {
+	rec := newRecorder(t)
+	notifier := newTestNotifier(t, SinkConfig{Type: SinkTypeWebhook, URL: rec.server.URL, Secret: "s3cret"})
+
+	notifier.SetPrevious(discovery.Result{
+		Networks: map[string]discovery.Network{
+			"devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "active"},
+		},
+	})
+
+	err := notifier.Notify(context.Background(), discovery.Result{
+		LastUpdate: time.Unix(100, 0).UTC(),
+		Networks: map[string]discovery.Network{
+			"devnet-1": {Name: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Status: "inactive"},
+			"devnet-2": {
+				Name:       "devnet-2",
+				Repository: "ethpandaops/fusaka-devnets",
+				Status:     "active",
+				Forks: &discovery.ForksConfig{
+					Consensus: map[string]discovery.ConsensusForkConfig{"fulu": {Epoch: 256}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, rec.bodies, 1)
+	assert.Equal(t, Sign("s3cret", rec.bodies[0]), rec.headers[0].Get(SignatureHeader))
+
+	var payload struct {
+		Events []Event `json:"events"`
+	}
+
+	require.NoError(t, json.Unmarshal(rec.bodies[0], &payload))
+	assert.Equal(t, []Event{
+		{Type: EventNetworkArchived, Network: "devnet-1", Repository: "ethpandaops/fusaka-devnets", Time: time.Unix(100, 0).UTC()},
+		{Type: EventNetworkAdded, Network: "devnet-2", Repository: "ethpandaops/fusaka-devnets", Time: time.Unix(100, 0).UTC()},
+	}, payload.Events)
+}
+
+func TestNotify_FirstAndPartialResultsAreSkipped(t *testing.T) {
+	rec := newRecorder(t)
+	notifier := newTestNotifier(t, SinkConfig{URL: rec.server.URL})
+
+	result := discovery.Result{
+		Networks: map[string]discovery.Network{"devnet-1": {Name: "devnet-1", Status: "active"}},
+	}
+
+	// The first result only sets the baseline.
+	require.NoError(t, notifier.Notify(context.Background(), result))
+
+	// A partial result doesn't move the baseline.
+	partial := discovery.Result{
+		Networks: map[string]discovery.Network{},
+		Partial:  true,
+	}
+	require.NoError(t, notifier.Notify(context.Background(), partial))
+
+	require.NoError(t, notifier.Notify(context.Background(), result))
+	assert.Empty(t, rec.bodies)
+}
+
+func TestSend_ChatPayloads(t *testing.T) {
+	slack := newRecorder(t)
+	discord := newRecorder(t)
+	notifier := newTestNotifier(t,
+		SinkConfig{Type: SinkTypeSlack, URL: slack.server.URL},
+		SinkConfig{Type: SinkTypeDiscord, URL: discord.server.URL},
+	)
+
+	err := notifier.Send(context.Background(), []Event{
+		{Type: EventForkScheduled, Network: "devnet-2", Repository: "ethpandaops/fusaka-devnets", Fork: "fulu", Activation: "epoch 256"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, slack.bodies, 1)
+	assert.JSONEq(t, `{"text":"Fork fulu scheduled on devnet-2 (ethpandaops/fusaka-devnets) at epoch 256"}`, string(slack.bodies[0]))
+	assert.Empty(t, slack.headers[0].Get(SignatureHeader))
+
+	require.Len(t, discord.bodies, 1)
+	assert.JSONEq(t, `{"content":"Fork fulu scheduled on devnet-2 (ethpandaops/fusaka-devnets) at epoch 256"}`, string(discord.bodies[0]))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+
+	// Multi-byte characters count as one and are never cut in half.
+	long := strings.Repeat("é", 2500)
+	truncated := truncate(long, discordMaxContentLength)
+
+	assert.True(t, utf8.ValidString(truncated))
+	assert.Equal(t, discordMaxContentLength, utf8.RuneCountInString(truncated))
+	assert.Equal(t, strings.Repeat("é", discordMaxContentLength-3)+"...", truncated)
+	assert.Equal(t, strings.Repeat("é", 2000), truncate(strings.Repeat("é", 2000), discordMaxContentLength))
+}
+
+func TestSend_Filters(t *testing.T) {
+	byRepo := newRecorder(t)
+	byEvent := newRecorder(t)
+	notifier := newTestNotifier(t,
+		SinkConfig{URL: byRepo.server.URL, Repositories: []string{"ethpandaops/glamsterdam-devnets"}},
+		SinkConfig{URL: byEvent.server.URL, Events: []string{EventForkScheduled}},
+	)
+
+	err := notifier.Send(context.Background(), []Event{
+		{Type: EventNetworkAdded, Network: "devnet-2", Repository: "ethpandaops/fusaka-devnets"},
+		{Type: EventForkScheduled, Network: "devnet-2", Repository: "ethpandaops/fusaka-devnets", Fork: "fulu"},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, byRepo.bodies)
+	require.Len(t, byEvent.bodies, 1)
+	assert.Contains(t, string(byEvent.bodies[0]), EventForkScheduled)
+	assert.NotContains(t, string(byEvent.bodies[0]), EventNetworkAdded)
+}
+
+func TestSend_SinkError(t *testing.T) {
+	failing := newRecorder(t)
+	failing.response = http.StatusInternalServerError
+	working := newRecorder(t)
+
+	notifier := newTestNotifier(t,
+		SinkConfig{Name: "failing", URL: failing.server.URL},
+		SinkConfig{Name: "working", URL: working.server.URL},
+	)
+
+	err := notifier.Send(context.Background(), []Event{{Type: EventNetworkAdded, Network: "devnet-2"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sink failing")
+
+	// Other sinks are still notified.
+	assert.Len(t, working.bodies, 1)
+}
+
+func TestEventsFromChangelog(t *testing.T) {
+	events := EventsFromChangelog(&changelog.Changelog{
+		Changes: []changelog.Change{
+			{Type: changelog.StatusChanged, Network: "devnet-1", From: "inactive", To: "active"},
+			{Type: changelog.ImageUpdated, Network: "devnet-1", Field: "lighthouse"},
+			{Type: changelog.ForkRescheduled, Network: "devnet-1", Field: "fulu"},
+		},
+	})
+
+	assert.Empty(t, events)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    SinkConfig
+		wantErr string
+	}{
+		{name: "valid", sink: SinkConfig{URL: "https://example.com/hook"}},
+		{name: "unsupported type", sink: SinkConfig{Type: "email", URL: "https://example.com"}, wantErr: "unsupported type"},
+		{name: "invalid url", sink: SinkConfig{URL: "example.com"}, wantErr: "url must be"},
+		{name: "unsupported event", sink: SinkConfig{URL: "https://example.com", Events: []string{"network_removed"}}, wantErr: "unsupported event type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Sinks: []SinkConfig{tt.sink}}
+			config.SetDefaults()
+
+			err := config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
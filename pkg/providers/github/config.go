@@ -0,0 +1,34 @@
+package github
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultProbeTimeout bounds a single service URL probe.
+const DefaultProbeTimeout = 2 * time.Second
+
+// Config is the GitHub provider's own section,
+// discovery.providers.github.config. The repositories to discover stay in
+// discovery.github.
+type Config struct {
+	// ProbeTimeout bounds a single request checking whether a service URL
+	// exists. Defaults to DefaultProbeTimeout.
+	ProbeTimeout time.Duration `mapstructure:"probeTimeout"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	if c.ProbeTimeout == 0 {
+		c.ProbeTimeout = DefaultProbeTimeout
+	}
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	if c.ProbeTimeout < 0 {
+		return fmt.Errorf("probeTimeout must not be negative, got %s", c.ProbeTimeout)
+	}
+
+	return nil
+}
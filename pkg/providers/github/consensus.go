@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// parseConsensusSpec reads the consensus spec of a network from config.yaml.
+func (p *Provider) parseConsensusSpec(
+	ctx context.Context,
+	reader *repoReader,
+	networkName string,
+) (*discovery.ConsensusSpec, error) {
+	configPath := path.Join(reader.paths.NetworkConfigs, networkName, "metadata", "config.yaml")
+
+	content, err := reader.readFile(ctx, configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config.yaml: %w", err)
+	}
+
+	spec, err := decodeConsensusSpec([]byte(content))
+	if err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("failed to parse config.yaml: %w", err)
+		}
+
+		// Values of an unexpected type are left out, the rest is decoded.
+		p.log.WithError(err).WithField("network", networkName).Debug("Some config.yaml values have an unexpected type")
+	}
+
+	return spec, nil
+}
+
+// decodeConsensusSpec decodes a config.yaml. Keys without a field of
+// discovery.ConsensusSpec go to ForkVersions or Extra, except for the fork
+// epochs and blob schedule. On a *yaml.TypeError the spec holds the values
+// that could be decoded.
+func decodeConsensusSpec(content []byte) (*discovery.ConsensusSpec, error) {
+	var doc struct {
+		discovery.ConsensusSpec `yaml:",inline"`
+		Rest                    map[string]yaml.Node `yaml:",inline"`
+	}
+
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+	}
+
+	spec := doc.ConsensusSpec
+
+	for key, node := range doc.Rest {
+		upperKey := strings.ToUpper(key)
+
+		switch {
+		case strings.HasSuffix(upperKey, "_FORK_VERSION"):
+			if spec.ForkVersions == nil {
+				spec.ForkVersions = make(map[string]string)
+			}
+
+			spec.ForkVersions[strings.ToLower(strings.TrimSuffix(upperKey, "_FORK_VERSION"))] = node.Value
+		case strings.HasSuffix(upperKey, "_FORK_EPOCH"), upperKey == "BLOB_SCHEDULE":
+			continue
+		default:
+			value, decodeErr := nodeValue(&node)
+			if decodeErr != nil {
+				continue
+			}
+
+			if spec.Extra == nil {
+				spec.Extra = make(map[string]any)
+			}
+
+			spec.Extra[key] = value
+		}
+	}
+
+	return &spec, err
+}
+
+// nodeValue decodes a YAML node, keeping hex scalars as written since in
+// config.yaml they are byte strings rather than numbers.
+func nodeValue(node *yaml.Node) (any, error) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!int" && strings.HasPrefix(strings.ToLower(node.Value), "0x") {
+		return node.Value, nil
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
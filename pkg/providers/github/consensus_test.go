@@ -0,0 +1,126 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+const testConfigYAML = `# Extends the mainnet preset
+PRESET_BASE: 'mainnet'
+CONFIG_NAME: fusaka-devnet-5
+
+TERMINAL_TOTAL_DIFFICULTY: 58750000000000000000000
+TERMINAL_BLOCK_HASH: 0x0000000000000000000000000000000000000000000000000000000000000000
+TERMINAL_BLOCK_HASH_ACTIVATION_EPOCH: 18446744073709551615
+
+MIN_GENESIS_ACTIVE_VALIDATOR_COUNT: 64
+MIN_GENESIS_TIME: 1750000000
+GENESIS_FORK_VERSION: 0x10000038
+GENESIS_DELAY: 60
+
+ALTAIR_FORK_VERSION: 0x20000038
+ALTAIR_FORK_EPOCH: 0
+FULU_FORK_VERSION: 0x70000038
+FULU_FORK_EPOCH: 256
+
+SLOT_DURATION_MS: 12000
+SECONDS_PER_ETH1_BLOCK: 12
+ETH1_FOLLOW_DISTANCE: 2048
+
+EJECTION_BALANCE: 16000000000
+MIN_PER_EPOCH_CHURN_LIMIT: 4
+CHURN_LIMIT_QUOTIENT: 65536
+MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT: 8
+MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA: 128000000000
+MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT: 256000000000
+
+DEPOSIT_CHAIN_ID: 7088110746
+DEPOSIT_NETWORK_ID: 7088110746
+DEPOSIT_CONTRACT_ADDRESS: 0x00000000219ab540356cBB839Cbe05303d7705Fa
+
+MAX_BLOBS_PER_BLOCK_ELECTRA: 9
+BLOB_SCHEDULE:
+  - EPOCH: 512
+    MAX_BLOBS_PER_BLOCK: 15
+
+MAX_PAYLOAD_SIZE: 10485760
+MESSAGE_DOMAIN_VALID_SNAPPY: 0x01000000
+BOOTNODES: [enr:-abc]
+`
+
+func TestDecodeConsensusSpec(t *testing.T) {
+	spec, err := decodeConsensusSpec([]byte(testConfigYAML))
+	require.NoError(t, err)
+
+	assert.Equal(t, &discovery.ConsensusSpec{
+		PresetBase: "mainnet",
+		ConfigName: "fusaka-devnet-5",
+		ForkVersions: map[string]string{
+			"genesis": "0x10000038",
+			"altair":  "0x20000038",
+			"fulu":    "0x70000038",
+		},
+		TerminalTotalDifficulty:             "58750000000000000000000",
+		TerminalBlockHash:                   "0x0000000000000000000000000000000000000000000000000000000000000000",
+		TerminalBlockHashActivationEpoch:    18446744073709551615,
+		MinGenesisActiveValidatorCount:      64,
+		MinGenesisTime:                      1750000000,
+		GenesisDelay:                        60,
+		SlotDurationMS:                      12000,
+		SecondsPerEth1Block:                 12,
+		Eth1FollowDistance:                  2048,
+		EjectionBalance:                     16000000000,
+		MinPerEpochChurnLimit:               4,
+		ChurnLimitQuotient:                  65536,
+		MaxPerEpochActivationChurnLimit:     8,
+		MinPerEpochChurnLimitElectra:        128000000000,
+		MaxPerEpochActivationExitChurnLimit: 256000000000,
+		DepositChainID:                      7088110746,
+		DepositNetworkID:                    7088110746,
+		DepositContractAddress:              "0x00000000219ab540356cBB839Cbe05303d7705Fa",
+		MaxBlobsPerBlockElectra:             9,
+		Extra: map[string]any{
+			"MAX_PAYLOAD_SIZE":            10485760,
+			"MESSAGE_DOMAIN_VALID_SNAPPY": "0x01000000",
+			"BOOTNODES":                   []any{"enr:-abc"},
+		},
+	}, spec)
+}
+
+func TestDecodeConsensusSpec_UnexpectedTypes(t *testing.T) {
+	spec, err := decodeConsensusSpec([]byte("PRESET_BASE: minimal\nEJECTION_BALANCE: lots\n"))
+	require.Error(t, err)
+
+	// The values that could be decoded are kept.
+	require.NotNil(t, spec)
+	assert.Equal(t, "minimal", spec.PresetBase)
+	assert.Zero(t, spec.EjectionBalance)
+
+	_, err = decodeConsensusSpec([]byte("PRESET_BASE: [unclosed"))
+	require.Error(t, err)
+}
+
+func TestDiscover_ConsensusSpec(t *testing.T) {
+	files := devnetFiles()
+	files["network-configs/devnet-1/metadata/config.yaml"] = testConfigYAML
+
+	repo := newFakeRepo("ethpandaops/test-devnets", files)
+	provider := newFakeRepoProvider(t, repo)
+
+	networks, err := provider.Discover(context.Background(), testRepoConfig(repo.name))
+	require.NoError(t, err)
+
+	spec := networks["devnet-1"].ConsensusSpec
+	require.NotNil(t, spec)
+	assert.Equal(t, "mainnet", spec.PresetBase)
+	assert.Equal(t, "0x70000038", spec.ForkVersions["fulu"])
+	assert.Equal(t, uint64(12000), spec.SlotDurationMS)
+
+	// Inactive networks aren't read.
+	assert.Nil(t, networks["devnet-2"].ConsensusSpec)
+}
@@ -0,0 +1,295 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// Execution-layer genesis files in the metadata directory of a network.
+const (
+	genesisJSONFile   = "genesis.json"
+	chainspecJSONFile = "chainspec.json"
+)
+
+// chainspecForks maps the chainspec.json parameters that activate an EIP to
+// the fork that introduced it. chainspec.json lists activations per EIP, not
+// per fork.
+var chainspecForks = map[string]string{
+	"eip140Transition":           "byzantium",
+	"eip145Transition":           "constantinople",
+	"eip1344Transition":          "istanbul",
+	"eip2929Transition":          "berlin",
+	"eip1559Transition":          "london",
+	"eip3855TransitionTimestamp": "shanghai",
+	"eip4844TransitionTimestamp": "cancun",
+	"eip7702TransitionTimestamp": "prague",
+	"eip7594TransitionTimestamp": "osaka",
+}
+
+// executionGenesis is what discovery reads from the execution-layer genesis
+// of a network.
+type executionGenesis struct {
+	chainID uint64
+	forks   map[string]discovery.ExecutionForkConfig
+	spec    discovery.ExecutionSpec
+}
+
+// parseExecutionGenesis reads the execution-layer genesis of a network from
+// genesis.json, taking the values it doesn't have from chainspec.json. The
+// genesis is nil if neither file could be read, the error reports the files
+// that exist but couldn't be read or parsed.
+func (p *Provider) parseExecutionGenesis(
+	ctx context.Context,
+	reader *repoReader,
+	networkName string,
+) (*executionGenesis, error) {
+	parsers := []struct {
+		file  string
+		parse func([]byte) (*executionGenesis, error)
+	}{
+		{genesisJSONFile, parseGenesisJSON},
+		{chainspecJSONFile, parseChainspecJSON},
+	}
+
+	var (
+		genesis *executionGenesis
+		errs    []error
+	)
+
+	for _, parser := range parsers {
+		filePath := path.Join(reader.paths.NetworkConfigs, networkName, "metadata", parser.file)
+
+		content, err := reader.readFile(ctx, filePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get %s: %w", parser.file, err))
+
+			continue
+		}
+
+		parsed, err := parser.parse([]byte(content))
+		if err != nil {
+			p.log.WithError(err).WithFields(logrus.Fields{
+				"network": networkName,
+				"file":    filePath,
+			}).Debug("Failed to parse execution-layer genesis")
+
+			errs = append(errs, fmt.Errorf("failed to parse %s: %w", parser.file, err))
+
+			continue
+		}
+
+		if genesis == nil {
+			genesis = parsed
+		} else {
+			genesis.fill(parsed)
+		}
+	}
+
+	return genesis, errors.Join(errs...)
+}
+
+// applyExecutionGenesis sets the chainId, execution forks and execution spec
+// of network from genesis.
+func applyExecutionGenesis(network *discovery.Network, genesis *executionGenesis) {
+	if genesis.chainID != 0 {
+		network.ChainID = genesis.chainID
+	}
+
+	if len(genesis.forks) > 0 {
+		if network.Forks == nil {
+			network.Forks = &discovery.ForksConfig{}
+		}
+
+		network.Forks.Execution = genesis.forks
+	}
+
+	if genesis.spec != (discovery.ExecutionSpec{}) {
+		spec := genesis.spec
+		network.ExecutionSpec = &spec
+	}
+}
+
+// fill sets the values of g that are missing from other.
+func (g *executionGenesis) fill(other *executionGenesis) {
+	if g.chainID == 0 {
+		g.chainID = other.chainID
+	}
+
+	for name, fork := range other.forks {
+		if _, ok := g.forks[name]; !ok {
+			g.forks[name] = fork
+		}
+	}
+
+	if g.spec.GasLimit == 0 {
+		g.spec.GasLimit = other.spec.GasLimit
+	}
+
+	if g.spec.DepositContractAddress == "" {
+		g.spec.DepositContractAddress = other.spec.DepositContractAddress
+	}
+
+	if g.spec.TerminalTotalDifficulty == "" {
+		g.spec.TerminalTotalDifficulty = other.spec.TerminalTotalDifficulty
+	}
+}
+
+// parseGenesisJSON decodes a genesis.json in the go-ethereum format, in which
+// config has a <fork>Block or <fork>Time entry per fork.
+func parseGenesisJSON(content []byte) (*executionGenesis, error) {
+	var doc struct {
+		Config   map[string]json.RawMessage `json:"config"`
+		GasLimit json.RawMessage            `json:"gasLimit"`
+	}
+
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Config == nil {
+		return nil, errors.New("no config")
+	}
+
+	genesis := &executionGenesis{forks: make(map[string]discovery.ExecutionForkConfig)}
+
+	for key, raw := range doc.Config {
+		switch {
+		case key == "chainId":
+			genesis.chainID, _ = parseQuantityUint64(raw)
+		case key == "terminalTotalDifficulty":
+			if ttd, ok := parseQuantity(raw); ok {
+				genesis.spec.TerminalTotalDifficulty = ttd.String()
+			}
+		case key == "depositContractAddress":
+			_ = json.Unmarshal(raw, &genesis.spec.DepositContractAddress)
+		case strings.HasSuffix(key, "Block"):
+			if block, ok := parseQuantityUint64(raw); ok {
+				genesis.forks[strings.ToLower(strings.TrimSuffix(key, "Block"))] = discovery.ExecutionForkConfig{Block: block}
+			}
+		case strings.HasSuffix(key, "Time"):
+			if timestamp, ok := parseQuantityUint64(raw); ok {
+				genesis.forks[strings.ToLower(strings.TrimSuffix(key, "Time"))] = discovery.ExecutionForkConfig{Timestamp: timestamp}
+			}
+		}
+	}
+
+	genesis.spec.GasLimit, _ = parseQuantityUint64(doc.GasLimit)
+
+	return genesis, nil
+}
+
+// parseChainspecJSON decodes a chainspec.json in the Nethermind format, in
+// which params has an activation per EIP, see chainspecForks.
+func parseChainspecJSON(content []byte) (*executionGenesis, error) {
+	var doc struct {
+		Params  map[string]json.RawMessage `json:"params"`
+		Genesis struct {
+			GasLimit json.RawMessage `json:"gasLimit"`
+		} `json:"genesis"`
+	}
+
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Params == nil {
+		return nil, errors.New("no params")
+	}
+
+	genesis := &executionGenesis{forks: make(map[string]discovery.ExecutionForkConfig)}
+
+	genesis.chainID, _ = parseQuantityUint64(doc.Params["chainID"])
+	if genesis.chainID == 0 {
+		genesis.chainID, _ = parseQuantityUint64(doc.Params["networkID"])
+	}
+
+	if ttd, ok := parseQuantity(doc.Params["terminalTotalDifficulty"]); ok {
+		genesis.spec.TerminalTotalDifficulty = ttd.String()
+	}
+
+	if raw, ok := doc.Params["depositContractAddress"]; ok {
+		_ = json.Unmarshal(raw, &genesis.spec.DepositContractAddress)
+	}
+
+	for param, fork := range chainspecForks {
+		activation, ok := parseQuantityUint64(doc.Params[param])
+		if !ok {
+			continue
+		}
+
+		if strings.HasSuffix(param, "Timestamp") {
+			genesis.forks[fork] = discovery.ExecutionForkConfig{Timestamp: activation}
+		} else {
+			genesis.forks[fork] = discovery.ExecutionForkConfig{Block: activation}
+		}
+	}
+
+	genesis.spec.GasLimit, _ = parseQuantityUint64(doc.Genesis.GasLimit)
+
+	return genesis, nil
+}
+
+// parseQuantity parses a JSON number, or a string with a hex (0x prefixed) or
+// decimal number.
+func parseQuantity(raw json.RawMessage) (*big.Int, bool) {
+	if len(raw) == 0 {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+
+	var text string
+
+	switch v := value.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = v
+	default:
+		return nil, false
+	}
+
+	base := 10
+	if hex, ok := strings.CutPrefix(strings.ToLower(text), "0x"); ok {
+		text, base = hex, 16
+	}
+
+	quantity, ok := new(big.Int).SetString(text, base)
+	if !ok || quantity.Sign() < 0 {
+		return nil, false
+	}
+
+	return quantity, true
+}
+
+// parseQuantityUint64 parses a quantity, see parseQuantity, that fits in 64
+// bits.
+func parseQuantityUint64(raw json.RawMessage) (uint64, bool) {
+	quantity, ok := parseQuantity(raw)
+	if !ok || !quantity.IsUint64() {
+		return 0, false
+	}
+
+	return quantity.Uint64(), true
+}
@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+const testGenesisJSON = `{
+  "config": {
+    "chainId": 7088110746,
+    "homesteadBlock": 0,
+    "daoForkSupport": true,
+    "londonBlock": "0x10",
+    "mergeNetsplitBlock": 0,
+    "terminalTotalDifficulty": 58750000000000000000000,
+    "terminalTotalDifficultyPassed": true,
+    "shanghaiTime": 0,
+    "cancunTime": 0,
+    "pragueTime": 1750000000,
+    "osakaTime": null,
+    "depositContractAddress": "0x00000000219ab540356cBB839Cbe05303d7705Fa"
+  },
+  "gasLimit": "0x2255100",
+  "alloc": {}
+}`
+
+const testChainspecJSON = `{
+  "name": "devnet",
+  "params": {
+    "chainID": "0x1a67bfc9a",
+    "terminalTotalDifficulty": "0x0",
+    "eip1559Transition": "0x0",
+    "eip4844TransitionTimestamp": "0x0",
+    "eip7702TransitionTimestamp": "0x684ee180",
+    "eip7594TransitionTimestamp": "0x68a00000"
+  },
+  "genesis": {
+    "gasLimit": "0x2255100"
+  }
+}`
+
+func TestParseGenesisJSON(t *testing.T) {
+	genesis, err := parseGenesisJSON([]byte(testGenesisJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(7088110746), genesis.chainID)
+	assert.Equal(t, map[string]discovery.ExecutionForkConfig{
+		"homestead":     {Block: 0},
+		"london":        {Block: 16},
+		"mergenetsplit": {Block: 0},
+		"shanghai":      {Timestamp: 0},
+		"cancun":        {Timestamp: 0},
+		"prague":        {Timestamp: 1750000000},
+	}, genesis.forks)
+	assert.Equal(t, discovery.ExecutionSpec{
+		GasLimit:                36000000,
+		DepositContractAddress:  "0x00000000219ab540356cBB839Cbe05303d7705Fa",
+		TerminalTotalDifficulty: "58750000000000000000000",
+	}, genesis.spec)
+
+	_, err = parseGenesisJSON([]byte(`{"alloc": {}}`))
+	require.Error(t, err)
+
+	_, err = parseGenesisJSON([]byte(`{"config": `))
+	require.Error(t, err)
+}
+
+func TestParseChainspecJSON(t *testing.T) {
+	genesis, err := parseChainspecJSON([]byte(testChainspecJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(7088110746), genesis.chainID)
+	assert.Equal(t, map[string]discovery.ExecutionForkConfig{
+		"london": {Block: 0},
+		"cancun": {Timestamp: 0},
+		"prague": {Timestamp: 1750000000},
+		"osaka":  {Timestamp: 1755316224},
+	}, genesis.forks)
+	assert.Equal(t, discovery.ExecutionSpec{
+		GasLimit:                36000000,
+		TerminalTotalDifficulty: "0",
+	}, genesis.spec)
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+		ok       bool
+	}{
+		{raw: `12`, expected: "12", ok: true},
+		{raw: `"12"`, expected: "12", ok: true},
+		{raw: `"0xC"`, expected: "12", ok: true},
+		{raw: `58750000000000000000000`, expected: "58750000000000000000000", ok: true},
+		{raw: `null`},
+		{raw: `-1`},
+		{raw: `1.5`},
+		{raw: `"0xzz"`},
+		{raw: `true`},
+		{raw: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			quantity, ok := parseQuantity([]byte(tt.raw))
+			require.Equal(t, tt.ok, ok)
+
+			if ok {
+				assert.Equal(t, tt.expected, quantity.String())
+			}
+		})
+	}
+}
+
+func TestDiscover_ExecutionGenesis(t *testing.T) {
+	files := devnetFiles()
+	files["network-configs/devnet-1/metadata/genesis.json"] = testGenesisJSON
+	files["network-configs/devnet-1/metadata/chainspec.json"] = testChainspecJSON
+
+	repo := newFakeRepo("ethpandaops/test-devnets", files)
+	provider := newFakeRepoProvider(t, repo)
+
+	networks, err := provider.Discover(context.Background(), testRepoConfig(repo.name))
+	require.NoError(t, err)
+
+	network := networks["devnet-1"]
+
+	// The EL chainId takes precedence over DEPOSIT_CHAIN_ID.
+	assert.Equal(t, uint64(7088110746), network.ChainID)
+
+	// genesis.json wins, chainspec.json fills in osaka.
+	require.NotNil(t, network.Forks)
+	assert.Equal(t, discovery.ExecutionForkConfig{Block: 16}, network.Forks.Execution["london"])
+	assert.Equal(t, discovery.ExecutionForkConfig{Timestamp: 1755316224}, network.Forks.Execution["osaka"])
+
+	require.NotNil(t, network.ExecutionSpec)
+	assert.Equal(t, uint64(36000000), network.ExecutionSpec.GasLimit)
+	assert.Equal(t, "58750000000000000000000", network.ExecutionSpec.TerminalTotalDifficulty)
+}
+
+func TestDiscover_InvalidExecutionGenesis(t *testing.T) {
+	files := devnetFiles()
+	files["network-configs/devnet-1/metadata/genesis.json"] = "not json"
+
+	repo := newFakeRepo("ethpandaops/test-devnets", files)
+	provider := newFakeRepoProvider(t, repo)
+
+	networks, err := provider.Discover(context.Background(), testRepoConfig(repo.name))
+	require.NoError(t, err)
+
+	// Without a usable execution-layer genesis, DEPOSIT_CHAIN_ID is used.
+	assert.Equal(t, uint64(7001), networks["devnet-1"].ChainID)
+	assert.Nil(t, networks["devnet-1"].ExecutionSpec)
+}
@@ -3,41 +3,36 @@ package github
 import (
 	"context"
 	"fmt"
+	"maps"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ethpandaops/cartographoor/pkg/chainclock"
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
 	"gopkg.in/yaml.v3"
 )
 
-// chainTiming holds timing parameters needed for timestamp calculations.
-type chainTiming struct {
-	genesisTime         uint64
-	slotsPerEpoch       uint64
-	slotDurationSeconds uint64
-}
+// farFutureEpoch is the epoch of forks that aren't scheduled.
+const farFutureEpoch = uint64(18446744073709551615)
 
 // parseConfigYAML extracts chainId, genesisTime, genesisDelay, fork epochs and blob schedule from config.yaml file.
 func (p *Provider) parseConfigYAML(
 	ctx context.Context,
-	owner, repo, networkName string,
+	reader *repoReader,
+	networkName string,
 ) (chainID uint64, genesisTime uint64, genesisDelay uint64, forks *discovery.ForksConfig, blobSchedule []discovery.BlobSchedule, err error) {
 	// Construct path to config.yaml
-	configPath := path.Join(networkConfigDir, networkName, "metadata", "config.yaml")
+	configPath := path.Join(reader.paths.NetworkConfigs, networkName, "metadata", "config.yaml")
 
 	// Try to get file content
-	fileContent, _, _, err := p.githubClient.Repositories.GetContents(ctx, owner, repo, configPath, nil)
+	content, err := reader.readFile(ctx, configPath)
 	if err != nil {
 		return 0, 0, 0, nil, nil, fmt.Errorf("failed to get config.yaml: %w", err)
 	}
 
-	// Decode content
-	content, err := fileContent.GetContent()
-	if err != nil {
-		return 0, 0, 0, nil, nil, fmt.Errorf("failed to decode config.yaml content: %w", err)
-	}
-
 	// Parse YAML
 	var configData map[string]any
 	if yamlErr := yaml.Unmarshal([]byte(content), &configData); yamlErr != nil {
@@ -113,60 +108,114 @@ func (p *Provider) parseConfigYAML(
 		}
 	}
 
-	// Extract timing parameters from config.
-	// NOTE: Currently assumes slot duration and slots per epoch are constant across all forks.
-	// If future forks change these values (e.g., 12s -> 6s slots), timestamp calculation
-	// will need to be updated to sum segments with different timing parameters per epoch range.
-	timing := chainTiming{
-		genesisTime:         genesisTime,
-		slotsPerEpoch:       p.extractSlotsPerEpoch(configData, networkName),
-		slotDurationSeconds: p.extractSlotDurationSeconds(configData, networkName),
+	// Extract the slot timing, which can change at forks.
+	clock, err := chainclock.New(genesisTime, p.extractTimingSegments(configData, networkName)...)
+	if err != nil {
+		return 0, 0, 0, nil, nil, fmt.Errorf("invalid slot timing in config.yaml: %w", err)
 	}
 
 	// Extract consensus forks (with timestamp calculation)
-	forks = p.extractConsensusForks(configData, networkName, timing)
+	forks = p.extractConsensusForks(configData, networkName, clock)
 
 	// Extract blob schedule
-	blobSchedule = p.extractBlobSchedule(configData, networkName, timing)
+	blobSchedule = p.extractBlobSchedule(configData, networkName, clock)
 
 	return chainID, genesisTime, genesisDelay, forks, blobSchedule, nil
 }
 
+// extractTimingSegments extracts the slot timing from config: the timing at
+// genesis, and the timing from each scheduled fork that changes it. Following
+// the config.yaml convention for values changed by a fork, those are set with
+// SLOT_DURATION_MS_<FORK> and SLOTS_PER_EPOCH_<FORK>.
+func (p *Provider) extractTimingSegments(configData map[string]any, networkName string) []chainclock.Segment {
+	segments := []chainclock.Segment{{
+		SlotsPerEpoch: p.extractSlotsPerEpoch(configData, networkName),
+		SlotDuration:  p.extractSlotDuration(configData, networkName),
+	}}
+
+	forkSegments := make(map[string]*chainclock.Segment)
+
+	for key, value := range configData {
+		upperKey := strings.ToUpper(key)
+
+		var fork string
+
+		switch {
+		case strings.HasPrefix(upperKey, "SLOT_DURATION_MS_"):
+			fork = strings.TrimPrefix(upperKey, "SLOT_DURATION_MS_")
+		case strings.HasPrefix(upperKey, "SLOTS_PER_EPOCH_"):
+			fork = strings.TrimPrefix(upperKey, "SLOTS_PER_EPOCH_")
+		default:
+			continue
+		}
+
+		parsed, ok := p.parseUint64Value(value, networkName, upperKey)
+		if !ok {
+			continue
+		}
+
+		epochVal, ok := configData[fork+"_FORK_EPOCH"]
+		if !ok {
+			p.log.WithField("network", networkName).WithField("field", upperKey).Debug("No fork epoch for slot timing, skipping")
+
+			continue
+		}
+
+		epoch, ok := p.parseEpochValue(epochVal, networkName, strings.ToLower(fork))
+		if !ok || epoch == farFutureEpoch {
+			continue
+		}
+
+		segment, ok := forkSegments[fork]
+		if !ok {
+			segment = &chainclock.Segment{StartEpoch: epoch}
+			forkSegments[fork] = segment
+		}
+
+		if strings.HasPrefix(upperKey, "SLOT_DURATION_MS_") {
+			segment.SlotDuration = time.Duration(parsed) * time.Millisecond
+		} else {
+			segment.SlotsPerEpoch = parsed
+		}
+	}
+
+	// Sorted, so forks at the same epoch are merged deterministically.
+	for _, fork := range slices.Sorted(maps.Keys(forkSegments)) {
+		segments = append(segments, *forkSegments[fork])
+	}
+
+	return segments
+}
+
 // extractSlotsPerEpoch extracts slots per epoch from config, defaulting to 32 (mainnet preset).
 func (p *Provider) extractSlotsPerEpoch(configData map[string]any, networkName string) uint64 {
-	const defaultSlotsPerEpoch = 32
-
 	if val, ok := configData["SLOTS_PER_EPOCH"]; ok {
 		if spe, ok := p.parseUint64Value(val, networkName, "SLOTS_PER_EPOCH"); ok {
 			return spe
 		}
 	}
 
-	return defaultSlotsPerEpoch
+	return chainclock.DefaultSlotsPerEpoch
 }
 
-// extractSlotDurationSeconds extracts slot duration from config in seconds.
-func (p *Provider) extractSlotDurationSeconds(configData map[string]any, networkName string) uint64 {
-	const defaultSlotDurationSeconds = 12
-
+// extractSlotDuration extracts slot duration from config, defaulting to 12s (mainnet preset).
+func (p *Provider) extractSlotDuration(configData map[string]any, networkName string) time.Duration {
 	// Use SLOT_DURATION_MS (SECONDS_PER_SLOT is deprecated)
 	if val, ok := configData["SLOT_DURATION_MS"]; ok {
 		if ms, ok := p.parseUint64Value(val, networkName, "SLOT_DURATION_MS"); ok {
-			return ms / 1000
+			return time.Duration(ms) * time.Millisecond
 		}
 	}
 
-	return defaultSlotDurationSeconds
+	return chainclock.DefaultSlotDuration
 }
 
 // extractConsensusForks extracts consensus fork configurations from the config data and calculates timestamps.
 func (p *Provider) extractConsensusForks(
 	configData map[string]any,
 	networkName string,
-	timing chainTiming,
+	clock *chainclock.Clock,
 ) *discovery.ForksConfig {
-	const farFutureEpoch = uint64(18446744073709551615)
-
 	consensusForks := make(map[string]discovery.ConsensusForkConfig)
 
 	for key, value := range configData {
@@ -194,7 +243,7 @@ func (p *Provider) extractConsensusForks(
 		}
 
 		// Calculate timestamp from epoch
-		timestamp := timing.genesisTime + (epoch * timing.slotsPerEpoch * timing.slotDurationSeconds)
+		timestamp := clock.EpochTimestamp(epoch)
 
 		// Add to consensus forks
 		consensusForks[forkName] = discovery.ConsensusForkConfig{
@@ -254,7 +303,7 @@ func (p *Provider) parseEpochValue(value any, networkName, forkName string) (uin
 func (p *Provider) extractBlobSchedule(
 	configData map[string]any,
 	networkName string,
-	timing chainTiming,
+	clock *chainclock.Clock,
 ) []discovery.BlobSchedule {
 	// Look for BLOB_SCHEDULE key
 	blobScheduleVal, ok := configData["BLOB_SCHEDULE"]
@@ -307,7 +356,7 @@ func (p *Provider) extractBlobSchedule(
 		}
 
 		// Calculate timestamp from epoch
-		timestamp := timing.genesisTime + (epoch * timing.slotsPerEpoch * timing.slotDurationSeconds)
+		timestamp := clock.EpochTimestamp(epoch)
 
 		blobSchedule = append(blobSchedule, discovery.BlobSchedule{
 			Epoch:            epoch,
@@ -2,19 +2,19 @@ package github
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ethpandaops/cartographoor/pkg/chainclock"
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractBlobSchedule(t *testing.T) {
 	// Test timing parameters
-	testTiming := chainTiming{
-		genesisTime:         1000,
-		slotsPerEpoch:       32,
-		slotDurationSeconds: 12,
-	}
+	testClock, err := chainclock.New(1000, chainclock.Segment{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second})
+	require.NoError(t, err)
 
 	tests := []struct {
 		name        string
@@ -37,8 +37,8 @@ func TestExtractBlobSchedule(t *testing.T) {
 				},
 			},
 			expected: []discovery.BlobSchedule{
-				{Epoch: 412672, Timestamp: testTiming.genesisTime + (412672 * testTiming.slotsPerEpoch * testTiming.slotDurationSeconds), MaxBlobsPerBlock: 15},
-				{Epoch: 419072, Timestamp: testTiming.genesisTime + (419072 * testTiming.slotsPerEpoch * testTiming.slotDurationSeconds), MaxBlobsPerBlock: 21},
+				{Epoch: 412672, Timestamp: 1000 + (412672 * 32 * 12), MaxBlobsPerBlock: 15},
+				{Epoch: 419072, Timestamp: 1000 + (419072 * 32 * 12), MaxBlobsPerBlock: 21},
 			},
 			expectEmpty: false,
 		},
@@ -53,7 +53,7 @@ func TestExtractBlobSchedule(t *testing.T) {
 				},
 			},
 			expected: []discovery.BlobSchedule{
-				{Epoch: 412672, Timestamp: testTiming.genesisTime + (412672 * testTiming.slotsPerEpoch * testTiming.slotDurationSeconds), MaxBlobsPerBlock: 15},
+				{Epoch: 412672, Timestamp: 1000 + (412672 * 32 * 12), MaxBlobsPerBlock: 15},
 			},
 			expectEmpty: false,
 		},
@@ -107,7 +107,7 @@ func TestExtractBlobSchedule(t *testing.T) {
 				log: log,
 			}
 
-			result := p.extractBlobSchedule(tt.configData, "test-network", testTiming)
+			result := p.extractBlobSchedule(tt.configData, "test-network", testClock)
 
 			if tt.expectEmpty {
 				assert.Nil(t, result, "Expected nil blob schedule")
@@ -124,3 +124,41 @@ func TestExtractBlobSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractTimingSegments(t *testing.T) {
+	p := &Provider{log: logrus.New()}
+
+	configData := map[string]any{
+		"SLOT_DURATION_MS":        12000,
+		"FULU_FORK_EPOCH":         100,
+		"GLOAS_FORK_EPOCH":        200,
+		"SLOT_DURATION_MS_GLOAS":  6000,
+		"HEZE_FORK_EPOCH":         uint64(18446744073709551615),
+		"SLOT_DURATION_MS_HEZE":   4000,
+		"SLOTS_PER_EPOCH_MISSING": 16,
+		"BLOB_SCHEDULE": []any{
+			map[string]any{"EPOCH": 300, "MAX_BLOBS_PER_BLOCK": 21},
+		},
+	}
+
+	segments := p.extractTimingSegments(configData, "test-network")
+	assert.Equal(t, []chainclock.Segment{
+		{SlotsPerEpoch: 32, SlotDuration: 12 * time.Second},
+		{StartEpoch: 200, SlotDuration: 6 * time.Second},
+	}, segments)
+
+	clock, err := chainclock.New(1000, segments...)
+	require.NoError(t, err)
+
+	gloas := uint64(1000 + 200*32*12)
+
+	forks := p.extractConsensusForks(configData, "test-network", clock)
+	require.NotNil(t, forks)
+	assert.Equal(t, uint64(1000+100*32*12), forks.Consensus["fulu"].Timestamp)
+	assert.Equal(t, gloas, forks.Consensus["gloas"].Timestamp)
+
+	// Epochs after the fork are 6 second slots long.
+	blobSchedule := p.extractBlobSchedule(configData, "test-network", clock)
+	require.Len(t, blobSchedule, 1)
+	assert.Equal(t, gloas+100*32*6, blobSchedule[0].Timestamp)
+}
@@ -6,25 +6,19 @@ import (
 	"strings"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
-	gh "github.com/google/go-github/v53/github"
-)
-
-const (
-	// Path to the images.yaml file in the repository.
-	imagesYamlPath = "ansible/inventories/%s/group_vars/all/images.yaml"
 )
 
 // getImages fetches and parses the images.yaml file for a network.
 func (p *Provider) getImages(
 	ctx context.Context,
-	client *gh.Client,
-	owner, repo, networkName string,
+	reader *repoReader,
+	networkName string,
 ) (*discovery.Images, error) {
 	// The images.yaml file is typically found in the ansible/inventories/{networkName}/group_vars/all/ directory.
-	imagePath := fmt.Sprintf(imagesYamlPath, networkName)
+	imagePath := reader.networkPath(reader.paths.ImagesFile, networkName)
 
-	fileContent, _, _, err := client.Repositories.GetContents(ctx, owner, repo, imagePath, nil)
-	if err != nil || fileContent == nil {
+	content, err := reader.readFile(ctx, imagePath)
+	if err != nil {
 		p.log.WithError(err).WithFields(map[string]any{
 			"network": networkName,
 			"path":    imagePath,
@@ -33,16 +27,8 @@ func (p *Provider) getImages(
 		return nil, err
 	}
 
-	// Get content from GitHub response
-	content, err := fileContent.GetContent()
-	if err != nil {
-		p.log.WithError(err).WithField("network", networkName).Debug("Failed to decode images.yaml content")
-
-		return nil, err
-	}
-
 	// Construct the GitHub URL to the file
-	fileURL := fmt.Sprintf("https://github.com/%s/%s/blob/master/%s", owner, repo, imagePath)
+	fileURL := fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", reader.owner, reader.repo, reader.snapshot.ref, imagePath)
 
 	// Parse the YAML content to extract client and tool images
 	clients, tools := p.parseImagesYaml(content, networkName)
@@ -0,0 +1,87 @@
+package github
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// hostLimiter bounds the number of concurrent requests to each host.
+type hostLimiter struct {
+	mutex sync.Mutex
+	limit int
+	hosts map[string]chan struct{}
+}
+
+// newHostLimiter creates a limiter of limit requests per host.
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{
+		limit: limit,
+		hosts: make(map[string]chan struct{}),
+	}
+}
+
+// setLimit changes the number of requests per host. Requests in flight finish
+// under the previous limit.
+func (l *hostLimiter) setLimit(limit int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limit == l.limit {
+		return
+	}
+
+	l.limit = limit
+	l.hosts = make(map[string]chan struct{})
+}
+
+// slots returns the semaphore of host.
+func (l *hostLimiter) slots(host string) chan struct{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	host = strings.ToLower(host)
+
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.hosts[host] = slots
+	}
+
+	return slots
+}
+
+// transport returns a transport that sends requests through base, waiting for
+// a free slot of the request's host. A nil base is http.DefaultTransport.
+func (l *hostLimiter) transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &hostLimitedTransport{limiter: l, base: base}
+}
+
+// hostLimitedTransport is an http.RoundTripper limited by a hostLimiter.
+type hostLimitedTransport struct {
+	limiter *hostLimiter
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *hostLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	slots := t.limiter.slots(req.URL.Host)
+
+	select {
+	case slots <- struct{}{}:
+	case <-req.Context().Done():
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return nil, req.Context().Err()
+	}
+
+	defer func() { <-slots }()
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,34 @@
+package github
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+)
+
+var (
+	serviceURLProbes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "github",
+		Name:      "service_url_probes_total",
+		Help:      "Service URL probes of active networks by service and outcome (reachable or unreachable).",
+	}, []string{"service", "outcome"})
+
+	fileReads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "github",
+		Name:      "file_reads_total",
+		Help:      "Repository files read during discovery by source (api or cache).",
+	}, []string{"source"})
+)
+
+// observeServiceURLProbe records the outcome of a service URL probe.
+func observeServiceURLProbe(service string, valid bool) {
+	outcome := "unreachable"
+	if valid {
+		outcome = "reachable"
+	}
+
+	serviceURLProbes.WithLabelValues(service, outcome).Inc()
+}
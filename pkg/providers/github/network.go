@@ -6,10 +6,8 @@ import (
 	"net/http"
 	"path"
 	"strings"
-	"time"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
-	gh "github.com/google/go-github/v53/github"
 )
 
 // NetworkConfig contains configuration for a network.
@@ -24,6 +22,7 @@ type NetworkConfig struct {
 	Status        string
 	ConfigFiles   []string
 	Domain        string
+	Applications  []discovery.Application
 	HiveURL       string
 	SelfHostedDNS bool
 	Images        struct {
@@ -34,22 +33,12 @@ type NetworkConfig struct {
 }
 
 // checkSelfHostedDNS checks if the network uses a self-hosted DNS server.
-func (p *Provider) checkSelfHostedDNS(
-	ctx context.Context,
-	client *gh.Client,
-	owner, repo, networkName string,
-) bool {
-	// Check for ansible/inventories/devnet-X/group_vars/dns_server.yaml
-	dnsConfigPath := fmt.Sprintf("ansible/inventories/%s/group_vars/dns_server.yaml", networkName)
-
-	_, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, dnsConfigPath, nil)
-	if err == nil || (resp != nil && resp.StatusCode == http.StatusOK) {
-		// File exists, network uses its own DNS
-		return true
-	}
+func (p *Provider) checkSelfHostedDNS(reader *repoReader, networkName string) bool {
+	// If ansible/inventories/devnet-X/group_vars/dns_server.yaml exists the
+	// network uses its own DNS, otherwise Cloudflare.
+	dnsConfigPath := path.Join(reader.networkPath(reader.paths.Inventories, networkName), "group_vars", "dns_server.yaml")
 
-	// File doesn't exist, network uses Cloudflare
-	return false
+	return reader.snapshot.isFile(dnsConfigPath)
 }
 
 // checkHiveAvailability checks if the hive is available for a network.
@@ -73,7 +62,12 @@ func (p *Provider) checkHiveAvailability(
 		networkName,
 	)
 
-	resp, err := p.httpClient.Get(hiveListingURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, hiveListingURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.httpClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -87,25 +81,32 @@ func (p *Provider) checkHiveAvailability(
 	return hiveURL, nil
 }
 
-// getNetworkConfigs gets the config files and domain for an active network.
+// getNetworkConfigs gets the config files, domain and applications of an
+// active network from its values.yaml. Problems with the file are returned as
+// warnings.
 func (p *Provider) getNetworkConfigs(
 	ctx context.Context,
-	client *gh.Client,
-	owner, repo, kubePath, networkName string,
-) ([]string, string) {
-	valuesPath := path.Join(kubePath, "config", "values.yaml")
-	fileContent, _, _, err := client.Repositories.GetContents(ctx, owner, repo, valuesPath, nil)
-
-	if err == nil && fileContent != nil {
-		// Parse values.yaml to extract domain and config files
-		return p.parseValuesYaml(ctx, fileContent, networkName)
+	reader *repoReader,
+	networkName string,
+) *networkValues {
+	valuesPath := reader.networkPath(reader.paths.ValuesFile, networkName)
+
+	content, err := reader.readFile(ctx, valuesPath)
+	if err != nil {
+		p.log.WithError(err).WithField("network", networkName).Debug("Failed to read values.yaml")
+
+		return &networkValues{Warnings: []string{err.Error()}}
 	}
 
-	return nil, ""
+	// Parse values.yaml to extract domain, config files and applications
+	return parseValuesYaml(content)
 }
 
-// createNetwork creates a discovery.Network from a NetworkConfig.
-func (p *Provider) createNetwork(ctx context.Context, config *NetworkConfig) discovery.Network {
+// createNetwork creates a discovery.Network from a NetworkConfig, along with
+// warnings about the metadata files that couldn't be read.
+func (p *Provider) createNetwork(ctx context.Context, reader *repoReader, config *NetworkConfig) (discovery.Network, []discovery.Warning) {
+	var warnings []discovery.Warning
+
 	network := discovery.Network{
 		Name:          config.Name,
 		Repository:    config.Repository,
@@ -114,14 +115,18 @@ func (p *Provider) createNetwork(ctx context.Context, config *NetworkConfig) dis
 		Status:        config.Status,
 		HiveURL:       config.HiveURL,
 		SelfHostedDNS: config.SelfHostedDNS,
-		LastUpdated:   time.Now(),
+		Applications:  config.Applications,
 	}
 
 	// If network is active, add service URLs and GenesisConfig
 	if config.Status == "active" {
 		if config.Domain != "" {
 			// Add service URLs
-			network.ServiceURLs = p.getServiceURLs(ctx, config.Domain)
+			network.ServiceURLs = p.getServiceURLs(ctx, config.Domain, config.Applications)
+
+			// The spec is the metadata directory of the network, at the same
+			// ref as its URL.
+			network.ServiceURLs.DevnetSpec = config.URL + "/metadata"
 
 			// Add GenesisConfig if we have config files
 			if len(config.ConfigFiles) > 0 {
@@ -144,7 +149,7 @@ func (p *Provider) createNetwork(ctx context.Context, config *NetworkConfig) dis
 		}
 
 		// Try to extract chainId, genesisTime, genesisDelay, fork epochs and blob schedule from config.yaml
-		chainID, genesisTime, genesisDelay, forks, blobSchedule, err := p.parseConfigYAML(ctx, config.Owner, config.Repo, config.Name)
+		chainID, genesisTime, genesisDelay, forks, blobSchedule, err := p.parseConfigYAML(ctx, reader, config.Name)
 		if err == nil {
 			// Set the ChainID in the Network struct
 			network.ChainID = chainID
@@ -167,9 +172,30 @@ func (p *Provider) createNetwork(ctx context.Context, config *NetworkConfig) dis
 		} else {
 			p.log.WithError(err).WithField("network", config.Name).Debug("Failed to parse config.yaml")
 		}
+
+		// Failures are logged by parseConfigYAML above.
+		if spec, err := p.parseConsensusSpec(ctx, reader, config.Name); err == nil {
+			network.ConsensusSpec = spec
+		}
+
+		// The execution-layer genesis has the chainId of the EL, which takes
+		// precedence over DEPOSIT_CHAIN_ID, and the EL fork activations.
+		genesis, err := p.parseExecutionGenesis(ctx, reader, config.Name)
+		if err != nil {
+			warnings = append(warnings, discovery.Warning{
+				Repository: config.Repository,
+				Network:    config.PrefixedName,
+				File:       path.Join(reader.paths.NetworkConfigs, config.Name, "metadata"),
+				Message:    err.Error(),
+			})
+		}
+
+		if genesis != nil {
+			applyExecutionGenesis(&network, genesis)
+		}
 	}
 
-	return network
+	return network, warnings
 }
 
 // buildGenesisConfig builds a GenesisConfig from network config files.
@@ -1,64 +1,159 @@
 package github
 
 import (
-	"context"
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
-	gh "github.com/google/go-github/v53/github"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
 )
 
 const (
-	networkConfigDir     = "network-configs"
-	kubernetesDir        = "kubernetes"
-	kubernetesArchiveDir = "kubernetes-archive"
-	active               = "active"
-	inactive             = "inactive"
-	unknown              = "unknown"
+	active   = "active"
+	inactive = "inactive"
+	unknown  = "unknown"
 )
 
-// parseValuesYaml decodes the content of values.yaml and extracts config file paths and domain.
-func (p *Provider) parseValuesYaml(
-	ctx context.Context,
-	fileContent *gh.RepositoryContent,
-	networkName string,
-) ([]string, string) {
-	// Get content from GitHub response
-	content, err := fileContent.GetContent()
+// applicationSections are the top-level keys of values.yaml that list the
+// deployed applications, if any.
+var applicationSections = []string{"applications", "apps"}
+
+// nonApplicationKeys are top-level keys of values.yaml that are never
+// applications.
+var nonApplicationKeys = []string{"config", "global", "domain"}
+
+// networkValues is what discovery reads from the values.yaml of a network.
+type networkValues struct {
+	Domain       string
+	ConfigFiles  []string
+	Applications []discovery.Application
+	// Warnings describe the parts of the file that weren't understood.
+	Warnings []string
+}
+
+// parseValuesYaml decodes the content of values.yaml. Parts that don't match
+// the expected layout are skipped with a warning. Content that isn't valid
+// YAML, such as values with Helm template expressions, falls back to
+// extracting the domain and config files line by line.
+func parseValuesYaml(content string) *networkValues {
+	var (
+		// root keeps the document order, which the domain search follows.
+		root yaml.Node
+		doc  map[string]any
+	)
+
+	err := yaml.Unmarshal([]byte(content), &root)
+	if err == nil {
+		err = root.Decode(&doc)
+	}
+
 	if err != nil {
-		p.log.WithError(err).WithField("network", networkName).Error("Failed to decode values.yaml content")
+		values := &networkValues{
+			Domain:      extractDomain(content),
+			ConfigFiles: extractConfigPaths(content),
+		}
+		values.warn("values.yaml is not valid YAML, read the domain and config files line by line: %v", err)
 
-		return nil, ""
+		return values
 	}
 
-	// Extract domain
-	domain := p.extractDomain(content)
+	values := &networkValues{}
 
-	// Extract config file paths
-	configPaths := p.extractConfigPaths(content)
+	values.Domain = values.findDomain(doc, &root)
+	values.ConfigFiles = values.configFiles(doc)
+	values.Applications = values.applications(doc)
 
-	return configPaths, domain
+	return values
 }
 
-// extractDomain extracts the domain from values.yaml content.
-func (p *Provider) extractDomain(content string) string {
-	var domain string
+// warn records a warning.
+func (v *networkValues) warn(format string, args ...any) {
+	v.Warnings = append(v.Warnings, fmt.Sprintf(format, args...))
+}
+
+// findDomain returns the domain of a network, set at the top level, in
+// global or in config, or otherwise the first domain key of root in document
+// order. The domain is optional, only a domain that isn't a string is warned
+// about.
+func (v *networkValues) findDomain(doc map[string]any, root *yaml.Node) string {
+	sections := []struct {
+		key    string
+		values map[string]any
+	}{
+		{key: "domain", values: doc},
+		{key: "global.domain", values: asMap(doc["global"])},
+		{key: "config.domain", values: asMap(doc["config"])},
+	}
+
+	for _, section := range sections {
+		switch domain := section.values["domain"].(type) {
+		case nil:
+			// Not set.
+		case string:
+			if domain != "" {
+				return domain
+			}
+		default:
+			v.warn("%s is not a string", section.key)
+		}
+	}
+
+	return searchDomain(root)
+}
+
+// searchDomain returns the first non-empty domain key of node in document
+// order, following aliases and merge keys.
+func searchDomain(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if domain := searchDomain(child); domain != "" {
+				return domain
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			if key.Value == "domain" && value.Kind == yaml.ScalarNode && value.ShortTag() == "!!str" && value.Value != "" {
+				return value.Value
+			}
+
+			if domain := searchDomain(value); domain != "" {
+				return domain
+			}
+		}
+	case yaml.AliasNode:
+		return searchDomain(node.Alias)
+	}
 
+	return ""
+}
+
+// extractDomain returns the value of the first domain key in content, found
+// line by line.
+func extractDomain(content string) string {
 	for line := range strings.SplitSeq(content, "\n") {
 		if strings.HasPrefix(strings.TrimSpace(line), "domain:") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) > 1 {
-				domain = strings.TrimSpace(parts[1])
+			_, value, _ := strings.Cut(line, ":")
 
-				break
-			}
+			return strings.Trim(strings.TrimSpace(value), `"'`)
 		}
 	}
 
-	return domain
+	return ""
 }
 
-// extractConfigPaths extracts the config file paths from values.yaml content.
-func (p *Provider) extractConfigPaths(content string) []string {
+// extractConfigPaths returns the "- path:" entries of config.files in content,
+// found line by line.
+func extractConfigPaths(content string) []string {
 	var (
 		configPaths     []string
 		inConfigSection = false
@@ -84,10 +179,8 @@ func (p *Provider) extractConfigPaths(content string) []string {
 
 		// Process file paths inside the files section
 		if inConfigSection && inFilesSection && strings.HasPrefix(trimmedLine, "- path:") {
-			parts := strings.SplitN(trimmedLine, ":", 2)
-			if len(parts) > 1 {
-				configPaths = append(configPaths, strings.TrimSpace(parts[1]))
-			}
+			_, value, _ := strings.Cut(trimmedLine, ":")
+			configPaths = append(configPaths, strings.Trim(strings.TrimSpace(value), `"'`))
 		}
 
 		// Only exit the config section if we're at a new top-level section (indicated by a line with no indentation)
@@ -99,3 +192,180 @@ func (p *Provider) extractConfigPaths(content string) []string {
 
 	return configPaths
 }
+
+// configFiles returns the paths of config.files, which are either mappings
+// with a path or plain paths. Both config and config.files are optional, only
+// sections that don't have the expected type are warned about.
+func (v *networkValues) configFiles(doc map[string]any) []string {
+	config := doc["config"]
+	if config == nil {
+		return nil
+	}
+
+	if asMap(config) == nil {
+		v.warn("config is not a mapping")
+
+		return nil
+	}
+
+	files := asMap(config)["files"]
+	if files == nil {
+		return nil
+	}
+
+	list, ok := files.([]any)
+	if !ok {
+		v.warn("config.files is not a list")
+
+		return nil
+	}
+
+	paths := make([]string, 0, len(list))
+
+	for i, file := range list {
+		switch f := file.(type) {
+		case string:
+			paths = append(paths, f)
+		case map[string]any:
+			if path, ok := f["path"].(string); ok && path != "" {
+				paths = append(paths, path)
+
+				continue
+			}
+
+			v.warn("config.files[%d] has no path", i)
+		default:
+			v.warn("config.files[%d] is neither a path nor a mapping", i)
+		}
+	}
+
+	return paths
+}
+
+// applications returns the deployed applications, sorted by name. They are
+// listed in an applications section, or else are the top-level mappings with
+// an enabled flag or an ingress. Applications with enabled: false aren't
+// deployed.
+func (v *networkValues) applications(doc map[string]any) []discovery.Application {
+	candidates := doc
+	explicit := false
+
+	for _, section := range applicationSections {
+		if apps, ok := doc[section]; ok {
+			candidates, explicit = asMap(apps), true
+
+			if candidates == nil {
+				v.warn("%s is not a mapping", section)
+			}
+
+			break
+		}
+	}
+
+	var applications []discovery.Application
+
+	for _, name := range slices.Sorted(maps.Keys(candidates)) {
+		app, ok := candidates[name].(map[string]any)
+		if !ok || (!explicit && slices.Contains(nonApplicationKeys, name)) {
+			continue
+		}
+
+		_, hasEnabled := app["enabled"]
+		_, hasIngress := app["ingress"]
+
+		if !explicit && !hasEnabled && !hasIngress {
+			continue
+		}
+
+		if enabled, ok := app["enabled"].(bool); hasEnabled && (!ok || !enabled) {
+			if !ok {
+				v.warn("%s.enabled is not a boolean", name)
+			}
+
+			continue
+		}
+
+		applications = append(applications, discovery.Application{
+			Name:  name,
+			Hosts: v.ingressHosts(name, app),
+		})
+	}
+
+	return applications
+}
+
+// ingressHosts returns the hosts of the enabled ingresses in the values of an
+// application, in the order they appear.
+func (v *networkValues) ingressHosts(name string, app map[string]any) []string {
+	var hosts []string
+
+	add := func(host string) {
+		if host != "" && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	var visit func(path string, node any)
+
+	visit = func(path string, node any) {
+		n, ok := node.(map[string]any)
+		if !ok {
+			return
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(n)) {
+			child := path + "." + key
+
+			ingress, ok := n[key].(map[string]any)
+			if key != "ingress" || !ok {
+				visit(child, n[key])
+
+				continue
+			}
+
+			if enabled, ok := ingress["enabled"].(bool); ok && !enabled {
+				continue
+			}
+
+			if host, ok := ingress["host"].(string); ok {
+				add(host)
+			}
+
+			hostList, ok := ingress["hosts"].([]any)
+			if !ok {
+				if _, exists := ingress["hosts"]; exists {
+					v.warn("%s.hosts is not a list", child)
+				}
+
+				continue
+			}
+
+			for i, item := range hostList {
+				switch h := item.(type) {
+				case string:
+					add(h)
+				case map[string]any:
+					host, _ := h["host"].(string)
+					if host == "" {
+						v.warn("%s.hosts[%d] has no host", child, i)
+					}
+
+					add(host)
+				default:
+					v.warn("%s.hosts[%d] is neither a host nor a mapping", child, i)
+				}
+			}
+		}
+	}
+
+	visit(name, app)
+
+	return hosts
+}
+
+// asMap returns node as a mapping, or nil if it isn't one.
+func asMap(node any) map[string]any {
+	m, _ := node.(map[string]any)
+
+	return m
+}
@@ -0,0 +1,203 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+func TestParseValuesYaml(t *testing.T) {
+	t.Run("Quoted and re-indented values", func(t *testing.T) {
+		values := parseValuesYaml(`
+global:
+    domain: "devnet-1.ethpandaops.io"   # quoted, with a comment
+config:
+    files:
+        - path: 'metadata/genesis.json'
+        - metadata/config.yaml
+`)
+
+		assert.Equal(t, "devnet-1.ethpandaops.io", values.Domain)
+		assert.Equal(t, []string{"metadata/genesis.json", "metadata/config.yaml"}, values.ConfigFiles)
+		assert.Empty(t, values.Warnings)
+	})
+
+	t.Run("Anchors and flow style", func(t *testing.T) {
+		values := parseValuesYaml(`
+common: &common
+  domain: devnet-2.ethpandaops.io
+config:
+  <<: *common
+  files: [{path: metadata/genesis.ssz}]
+`)
+
+		assert.Equal(t, "devnet-2.ethpandaops.io", values.Domain)
+		assert.Equal(t, []string{"metadata/genesis.ssz"}, values.ConfigFiles)
+	})
+
+	t.Run("Nested domain", func(t *testing.T) {
+		values := parseValuesYaml(`
+network:
+  ingress:
+    domain: devnet-3.ethpandaops.io
+config:
+  files: []
+`)
+
+		assert.Equal(t, "devnet-3.ethpandaops.io", values.Domain)
+	})
+
+	t.Run("Nested domain in document order", func(t *testing.T) {
+		values := parseValuesYaml(`
+network:
+  zeta:
+    domain: devnet-8.ethpandaops.io
+  alpha:
+    domain: other.ethpandaops.io
+`)
+
+		assert.Equal(t, "devnet-8.ethpandaops.io", values.Domain)
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		values := parseValuesYaml("")
+
+		assert.Empty(t, values.Domain)
+		assert.Empty(t, values.Warnings)
+	})
+
+	t.Run("Applications and ingress hosts", func(t *testing.T) {
+		values := parseValuesYaml(`
+domain: devnet-4.ethpandaops.io
+config:
+  files: []
+dora:
+  enabled: true
+  ingress:
+    enabled: true
+    hosts:
+      - host: dora.devnet-4.ethpandaops.io
+      - dora.devnet-4.ethpandaops.io
+faucet:
+  ingress:
+    host: faucet.devnet-4.ethpandaops.io
+blobscan:
+  enabled: false
+  ingress:
+    host: blobscan.devnet-4.ethpandaops.io
+forkmon:
+  enabled: true
+  ingress:
+    enabled: false
+    host: forkmon.devnet-4.ethpandaops.io
+replicas: 3
+`)
+
+		assert.Equal(t, []discovery.Application{
+			{Name: "dora", Hosts: []string{"dora.devnet-4.ethpandaops.io"}},
+			{Name: "faucet", Hosts: []string{"faucet.devnet-4.ethpandaops.io"}},
+			{Name: "forkmon"},
+		}, values.Applications)
+		assert.Empty(t, values.Warnings)
+	})
+
+	t.Run("Applications section", func(t *testing.T) {
+		values := parseValuesYaml(`
+domain: devnet-5.ethpandaops.io
+config:
+  files: []
+applications:
+  rpc:
+    ingress:
+      hosts: [rpc.devnet-5.ethpandaops.io]
+  tracoor: {}
+`)
+
+		assert.Equal(t, []discovery.Application{
+			{Name: "rpc", Hosts: []string{"rpc.devnet-5.ethpandaops.io"}},
+			{Name: "tracoor"},
+		}, values.Applications)
+	})
+
+	t.Run("Unknown layout", func(t *testing.T) {
+		values := parseValuesYaml(`
+config:
+  files:
+    - path: metadata/genesis.json
+    - {name: config}
+    - 42
+dora:
+  enabled: "yes"
+faucet:
+  ingress:
+    hosts: faucet.example.com
+`)
+
+		assert.Empty(t, values.Domain)
+		assert.Equal(t, []string{"metadata/genesis.json"}, values.ConfigFiles)
+		assert.Equal(t, []discovery.Application{{Name: "faucet"}}, values.Applications)
+		assert.Equal(t, []string{
+			"config.files[1] has no path",
+			"config.files[2] is neither a path nor a mapping",
+			"dora.enabled is not a boolean",
+			"faucet.ingress.hosts is not a list",
+		}, values.Warnings)
+	})
+
+	t.Run("Optional keys", func(t *testing.T) {
+		values := parseValuesYaml(`
+dora:
+  enabled: true
+`)
+
+		assert.Empty(t, values.Domain)
+		assert.Empty(t, values.ConfigFiles)
+		assert.Empty(t, values.Warnings, "missing optional keys are not warned about")
+
+		values = parseValuesYaml(`
+global:
+  domain: [devnet-7.ethpandaops.io]
+config:
+  files:
+`)
+
+		assert.Empty(t, values.Domain)
+		assert.Equal(t, []string{"global.domain is not a string"}, values.Warnings)
+
+		values = parseValuesYaml(`
+config:
+  files: metadata/genesis.json
+`)
+
+		assert.Equal(t, []string{"config.files is not a list"}, values.Warnings)
+
+		values = parseValuesYaml(`
+config: metadata
+`)
+
+		assert.Equal(t, []string{"config is not a mapping"}, values.Warnings)
+	})
+
+	t.Run("Invalid YAML falls back to line-based extraction", func(t *testing.T) {
+		values := parseValuesYaml(`
+global:
+  domain: devnet-6.ethpandaops.io
+  image: {{ .Values.image }}
+config:
+  files:
+    - path: metadata/genesis.json
+    - path: "metadata/config.yaml"
+dora:
+  enabled: true
+`)
+
+		assert.Equal(t, "devnet-6.ethpandaops.io", values.Domain)
+		assert.Equal(t, []string{"metadata/genesis.json", "metadata/config.yaml"}, values.ConfigFiles)
+		assert.Empty(t, values.Applications)
+		require.Len(t, values.Warnings, 1)
+		assert.Contains(t, values.Warnings[0], "values.yaml is not valid YAML")
+	})
+}
@@ -2,40 +2,110 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"net/http"
 	"path"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	gh "github.com/google/go-github/v53/github"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/semaphore"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/ethpandaops/cartographoor/pkg/githubapi"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/ratelimit"
+)
+
+func init() {
+	discovery.RegisterProviderFactory("github", func(log *logrus.Logger, deps discovery.ProviderDeps, raw map[string]any) (discovery.Provider, error) {
+		var config Config
+		if err := discovery.DecodeProviderConfig(raw, &config); err != nil {
+			return nil, err
+		}
+
+		return NewProvider(log, deps, config)
+	})
+}
+
+// Compile-time interface checks.
+var (
+	_ discovery.ScopedProvider   = (*Provider)(nil)
+	_ discovery.WarningProvider  = (*Provider)(nil)
+	_ discovery.RepositoryLister = (*Provider)(nil)
 )
 
 // Provider implements the discovery.Provider interface for GitHub.
 type Provider struct {
-	log          *logrus.Logger
+	log         *logrus.Logger
+	httpClient  *http.Client
+	probeClient *http.Client
+	hosts       *hostLimiter
+	auth        oauth2.TokenSource
+	httpCache   *httpcache.Cache
+	rateLimits  *ratelimit.Tracker
+
+	// githubClient, if set, is used instead of the clients built by getClient.
 	githubClient *gh.Client
-	httpClient   *http.Client
+
+	// client is the GitHub client built by getClient for clientToken. It is
+	// rebuilt when a config reload changes discovery.github.token.
+	clientMutex sync.Mutex
+	client      *gh.Client
+	clientToken string
+
+	// caches holds the tree and file contents of each repository from the last
+	// run, so unchanged files aren't fetched again.
+	cacheMutex sync.Mutex
+	caches     map[string]*repoCache
+	// hiveURLs holds the last hive check of each network, reused while the
+	// GitHub API budget is low.
+	hiveURLs map[string]string
 }
 
-// NewProvider creates a new GitHub provider.
-func NewProvider(log *logrus.Logger, httpClient *http.Client) (*Provider, error) {
+// NewProvider creates a new GitHub provider. GitHub API calls are
+// authenticated by deps.GitHubAuth, revalidated through deps.HTTPCache and
+// rate limits are tracked by deps.RateLimits; all of them may be nil.
+func NewProvider(log *logrus.Logger, deps discovery.ProviderDeps, config Config) (*Provider, error) {
+	config.SetDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid github provider config: %w", err)
+	}
+
 	log = log.WithField("provider", "github").Logger
 
-	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: 10 * time.Second,
-		}
+	// All requests of the provider share the per-host limits.
+	hosts := newHostLimiter(discovery.DefaultConcurrentPerHost)
+
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
 	}
+	if deps.HTTPClient != nil {
+		*httpClient = *deps.HTTPClient
+	}
+
+	httpClient.Transport = hosts.transport(httpClient.Transport)
 
 	return &Provider{
 		log:        log,
 		httpClient: httpClient,
+		probeClient: &http.Client{
+			Timeout:   config.ProbeTimeout,
+			Transport: hosts.transport(nil),
+		},
+		hosts:      hosts,
+		auth:       deps.GitHubAuth,
+		httpCache:  deps.HTTPCache,
+		rateLimits: deps.RateLimits,
+		caches:     make(map[string]*repoCache),
+		hiveURLs:   make(map[string]string),
 	}, nil
 }
 
@@ -46,35 +116,170 @@ func (p *Provider) Name() string {
 
 // Discover discovers networks using GitHub.
 func (p *Provider) Discover(ctx context.Context, config discovery.Config) (map[string]discovery.Network, error) {
+	networks, _, err := p.DiscoverWithWarnings(ctx, config, nil)
+
+	return networks, err
+}
+
+// DiscoverRepositories discovers networks in the given repositories only.
+func (p *Provider) DiscoverRepositories(ctx context.Context, config discovery.Config, repositories []string) (map[string]discovery.Network, error) {
+	networks, _, err := p.DiscoverWithWarnings(ctx, config, repositories)
+
+	return networks, err
+}
+
+// DiscoverWithWarnings discovers networks using GitHub, in the given
+// repositories only if they are not nil, and reports the parts of them that
+// couldn't be understood as warnings.
+func (p *Provider) DiscoverWithWarnings(
+	ctx context.Context,
+	config discovery.Config,
+	repositories []string,
+) (map[string]discovery.Network, []discovery.Warning, error) {
+	if repositories != nil {
+		scoped := make([]discovery.GitHubRepositoryConfig, 0, len(repositories))
+
+		for _, repoConfig := range config.GitHub.Repositories {
+			if slices.ContainsFunc(repositories, func(name string) bool { return strings.EqualFold(name, repoConfig.Name) }) {
+				scoped = append(scoped, repoConfig)
+			}
+		}
+
+		if len(scoped) == 0 {
+			return make(map[string]discovery.Network), nil, nil
+		}
+
+		config.GitHub.Repositories = scoped
+	}
+
 	if len(config.GitHub.Repositories) == 0 {
-		return nil, fmt.Errorf("no repositories configured")
+		return nil, nil, fmt.Errorf("no repositories configured")
 	}
 
-	// We require a token to be set in production, otherwise we'll just get rate-limited.
+	// We require credentials in production, otherwise we'll just get rate-limited.
 	// Skip this check if the client is already set (for testing purposes)
-	if config.GitHub.Token == "" && p.githubClient == nil {
-		return nil, fmt.Errorf("no GitHub token configured")
+	if p.auth == nil && config.GitHub.Token == "" && p.githubClient == nil {
+		return nil, nil, fmt.Errorf("no GitHub token configured")
 	}
 
+	concurrency := config.GitHub.Concurrency
+	concurrency.SetDefaults()
+
+	p.hosts.setLimit(concurrency.PerHost)
+
 	// Create GitHub client
-	githubClient := p.getClient(ctx, config.GitHub.Token)
+	githubClient := p.getClient(config.GitHub.Token)
+
+	type repoResult struct {
+		networks map[string]discovery.Network
+		warnings []discovery.Warning
+		err      error
+	}
 
-	networks := make(map[string]discovery.Network)
+	var (
+		networks = make(map[string]discovery.Network)
+		warnings []discovery.Warning
+		repoErrs = make(discovery.RepositoryErrors)
+		results  = make([]repoResult, len(config.GitHub.Repositories))
+		repoSem  = semaphore.NewWeighted(int64(concurrency.Repositories))
+		workers  = &networkWorkers{
+			sem:     semaphore.NewWeighted(int64(concurrency.Networks)),
+			timeout: concurrency.NetworkTimeout,
+		}
+		wg sync.WaitGroup
+	)
 
 	// Discover networks for each repository
-	for _, repoConfig := range config.GitHub.Repositories {
-		discoveredNetworks, err := p.discoverRepositoryNetworks(ctx, githubClient, repoConfig)
-		if err != nil {
+	for i, repoConfig := range config.GitHub.Repositories {
+		if err := repoSem.Acquire(ctx, 1); err != nil {
+			results[i].err = err
+
+			continue
+		}
+
+		wg.Go(func() {
+			defer repoSem.Release(1)
+
+			results[i].networks, results[i].warnings, results[i].err = p.discoverRepositoryNetworks(ctx, githubClient, repoConfig, workers)
+		})
+	}
+
+	wg.Wait()
+
+	// Merge in config order, so a network in several repositories always comes
+	// from the last one.
+	for i, repoConfig := range config.GitHub.Repositories {
+		if err := results[i].err; err != nil {
 			p.log.WithError(err).WithField("repository", repoConfig.Name).Error("Failed to discover networks in repository")
 
+			repoErrs[repoConfig.Name] = err
+
 			continue
 		}
 
 		// Add discovered networks to the result
-		maps.Copy(networks, discoveredNetworks)
+		maps.Copy(networks, results[i].networks)
+		warnings = append(warnings, results[i].warnings...)
+	}
+
+	// Report failed repositories alongside the networks that were discovered,
+	// so the discovery service can fall back to their last good networks.
+	if len(repoErrs) > 0 {
+		return networks, warnings, repoErrs
+	}
+
+	return networks, warnings, nil
+}
+
+// ListRepositories lists the repositories of org, for the repository sources.
+func (p *Provider) ListRepositories(ctx context.Context, config discovery.Config, org string) ([]discovery.SourceRepository, error) {
+	if p.auth == nil && config.GitHub.Token == "" && p.githubClient == nil {
+		return nil, fmt.Errorf("no GitHub token configured")
+	}
+
+	var (
+		githubClient = p.getClient(config.GitHub.Token)
+		opts         = &gh.RepositoryListByOrgOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+		repositories []discovery.SourceRepository
+	)
+
+	for {
+		repos, resp, err := githubClient.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+
+		for _, repo := range repos {
+			repositories = append(repositories, discovery.SourceRepository{
+				Name:        repo.GetFullName(),
+				Description: repo.GetDescription(),
+				Homepage:    repo.GetHomepage(),
+				URL:         repo.GetHTMLURL(),
+				Topics:      repo.Topics,
+				Archived:    repo.GetArchived(),
+				Fork:        repo.GetFork(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
 	}
 
-	return networks, nil
+	p.log.WithFields(logrus.Fields{
+		"org":          org,
+		"repositories": len(repositories),
+	}).Debug("Listed repositories")
+
+	return repositories, nil
+}
+
+// networkWorkers bounds the networks processed at once across repositories.
+type networkWorkers struct {
+	sem     *semaphore.Weighted
+	timeout time.Duration
 }
 
 // discoverRepositoryNetworks discovers networks in a specific repository.
@@ -82,7 +287,8 @@ func (p *Provider) discoverRepositoryNetworks(
 	ctx context.Context,
 	githubClient *gh.Client,
 	repoConfig discovery.GitHubRepositoryConfig,
-) (map[string]discovery.Network, error) {
+	workers *networkWorkers,
+) (map[string]discovery.Network, []discovery.Warning, error) {
 	var (
 		repoPath   = repoConfig.Name
 		namePrefix = repoConfig.NamePrefix
@@ -90,7 +296,7 @@ func (p *Provider) discoverRepositoryNetworks(
 	)
 
 	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid repository path: %s", repoPath)
+		return nil, nil, fmt.Errorf("invalid repository path: %s", repoPath)
 	}
 
 	owner, repo := parts[0], parts[1]
@@ -100,73 +306,186 @@ func (p *Provider) discoverRepositoryNetworks(
 		"namePrefix": namePrefix,
 	}).Info("Discovering networks in repository")
 
-	// Check if network-configs directory exists
-	netConfigPath := networkConfigDir
+	// Fetch the repository tree, reusing the last one if nothing was pushed
+	p.cacheMutex.Lock()
+	cache := p.caches[repoPath]
+	p.cacheMutex.Unlock()
 
-	_, dirContent, _, err := githubClient.Repositories.GetContents(ctx, owner, repo, netConfigPath, nil)
+	var previous *repoSnapshot
+	if cache != nil {
+		previous = cache.snapshot
+	}
+
+	paths := repoConfig.Paths.WithDefaults()
+
+	snapshot, err := loadSnapshot(ctx, githubClient, owner, repo, repoConfig.Ref, snapshotDirs(paths), previous)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get contents of network-configs directory: %w", err)
+		return nil, nil, err
 	}
 
-	networks := make(map[string]discovery.Network)
+	// Check if network-configs directory exists
+	netConfigPath := paths.NetworkConfigs
 
-	// Process directories in network-configs
-	for _, content := range dirContent {
-		if *content.Type != "dir" {
-			continue
-		}
+	if !snapshot.isDir(netConfigPath) {
+		return nil, nil, fmt.Errorf("no %s directory at %s", netConfigPath, snapshot.commitSHA)
+	}
+
+	var (
+		reader   = newRepoReader(githubClient, owner, repo, paths, snapshot, cache)
+		names    = snapshot.subdirs(netConfigPath)
+		configs  = make([]*NetworkConfig, len(names))
+		results  = make([]discovery.Network, len(names))
+		found    = make([][]discovery.Warning, len(names))
+		networks = make(map[string]discovery.Network, len(names))
+		warnings []discovery.Warning
+		wg       sync.WaitGroup
+	)
 
+	// Process directories in network-configs
+	for i, name := range names {
 		networkConfig := &NetworkConfig{
-			Name:         *content.Name,
-			PrefixedName: *content.Name,
+			Name:         name,
+			PrefixedName: name,
 			Repository:   repoPath,
 			Owner:        owner,
 			Repo:         repo,
-			Path:         path.Join(netConfigPath, *content.Name),
-			URL:          *content.HTMLURL,
+			Path:         path.Join(netConfigPath, name),
 		}
 
+		networkConfig.URL = fmt.Sprintf("https://github.com/%s/%s/tree/%s/%s", owner, repo, snapshot.ref, networkConfig.Path)
+
 		// Apply prefix if configured
 		if namePrefix != "" {
 			networkConfig.PrefixedName = namePrefix + networkConfig.Name
 		}
 
-		// Determine network status, configs, domain, and images
-		var images *discovery.Images
+		configs[i] = networkConfig
 
-		networkConfig.Status, networkConfig.ConfigFiles, networkConfig.Domain, images, networkConfig.HiveURL, networkConfig.SelfHostedDNS = p.getNetworkDetails(
-			ctx, githubClient, owner, repo, networkConfig.Name,
-		)
-
-		// Copy images data to network config if available
-		if images != nil {
-			networkConfig.Images.URL = images.URL
-			networkConfig.Images.Clients = images.Clients
-			networkConfig.Images.Tools = images.Tools
+		if err = workers.sem.Acquire(ctx, 1); err != nil {
+			break
 		}
 
-		// Create network and add to result
-		networks[networkConfig.PrefixedName] = p.createNetwork(ctx, networkConfig)
+		wg.Go(func() {
+			defer workers.sem.Release(1)
+
+			results[i], found[i] = p.processNetwork(ctx, reader, networkConfig, workers.timeout)
+		})
+	}
+
+	wg.Wait()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create networks and add to result
+	for i, networkConfig := range configs {
+		networks[networkConfig.PrefixedName] = results[i]
+		warnings = append(warnings, found[i]...)
 	}
 
-	return networks, nil
+	// Keep the files read in this run for the next one
+	p.cacheMutex.Lock()
+	p.caches[repoPath] = reader.cache()
+	p.cacheMutex.Unlock()
+
+	p.log.WithFields(logrus.Fields{
+		"repository":   repoPath,
+		"commit":       snapshot.commitSHA,
+		"networks":     len(networks),
+		"filesFetched": reader.fetched,
+		"filesCached":  len(reader.blobs) - reader.fetched,
+	}).Debug("Discovered networks in repository")
+
+	return networks, warnings, nil
 }
 
-// getClient returns a GitHub client.
-func (p *Provider) getClient(ctx context.Context, token string) *gh.Client {
+// processNetwork determines the details of a network within timeout and
+// creates it, along with the warnings about its files.
+func (p *Provider) processNetwork(
+	ctx context.Context,
+	reader *repoReader,
+	networkConfig *NetworkConfig,
+	timeout time.Duration,
+) (discovery.Network, []discovery.Warning) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Determine network status, configs, domain, applications and images
+	var (
+		values *networkValues
+		images *discovery.Images
+	)
+
+	networkConfig.Status, values, images, networkConfig.HiveURL, networkConfig.SelfHostedDNS = p.getNetworkDetails(
+		ctx, reader, networkConfig.Name,
+	)
+
+	networkConfig.ConfigFiles = values.ConfigFiles
+	networkConfig.Domain = values.Domain
+	networkConfig.Applications = values.Applications
+
+	var warnings []discovery.Warning
+
+	for _, warning := range values.Warnings {
+		warnings = append(warnings, discovery.Warning{
+			Repository: networkConfig.Repository,
+			Network:    networkConfig.PrefixedName,
+			File:       reader.networkPath(reader.paths.ValuesFile, networkConfig.Name),
+			Message:    warning,
+		})
+	}
+
+	// Copy images data to network config if available
+	if images != nil {
+		networkConfig.Images.URL = images.URL
+		networkConfig.Images.Clients = images.Clients
+		networkConfig.Images.Tools = images.Tools
+	}
+
+	network, networkWarnings := p.createNetwork(ctx, reader, networkConfig)
+	warnings = append(warnings, networkWarnings...)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		p.log.WithFields(logrus.Fields{
+			"network": networkConfig.PrefixedName,
+			"timeout": timeout,
+		}).Warn("Timed out processing network, some details are missing")
+	}
+
+	return network, warnings
+}
+
+// getClient returns a GitHub client, authenticated with token if no
+// credentials were passed to the provider. The client is reused until the
+// token changes.
+func (p *Provider) getClient(token string) *gh.Client {
 	if p.githubClient != nil {
 		return p.githubClient
 	}
 
-	if token != "" {
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: token},
-		)
-		tc := oauth2.NewClient(ctx, ts)
-		p.githubClient = gh.NewClient(tc)
-	} else {
-		p.githubClient = gh.NewClient(nil)
+	p.clientMutex.Lock()
+	defer p.clientMutex.Unlock()
+
+	if p.client != nil && (p.auth != nil || token == p.clientToken) {
+		return p.client
+	}
+
+	if p.client != nil {
+		p.log.Info("GitHub token changed, rebuilding the GitHub client")
 	}
 
-	return p.githubClient
+	auth := p.auth
+	if auth == nil {
+		auth = githubapi.StaticTokenSource(token)
+	}
+
+	// GitHub API calls count against the per-host limit like any other request.
+	httpClient := githubapi.NewHTTPClient(auth, p.httpCache, p.rateLimits)
+	httpClient.Transport = p.hosts.transport(httpClient.Transport)
+
+	p.client = gh.NewClient(httpClient)
+	p.clientToken = token
+
+	return p.client
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,12 +19,57 @@ import (
 
 func TestProvider_Name(t *testing.T) {
 	log := logrus.New()
-	provider, err := NewProvider(log, nil)
+	provider, err := NewProvider(log, discovery.ProviderDeps{}, Config{})
 	require.NoError(t, err)
 
 	assert.Equal(t, "github", provider.Name())
 }
 
+func TestProvider_Config(t *testing.T) {
+	provider, err := NewProvider(logrus.New(), discovery.ProviderDeps{}, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProbeTimeout, provider.probeClient.Timeout)
+
+	provider, err = NewProvider(logrus.New(), discovery.ProviderDeps{}, Config{ProbeTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, provider.probeClient.Timeout)
+
+	_, err = NewProvider(logrus.New(), discovery.ProviderDeps{}, Config{ProbeTimeout: -time.Second})
+	require.ErrorContains(t, err, "probeTimeout must not be negative")
+}
+
+func TestProvider_GetClient_TokenChange(t *testing.T) {
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(logrus.New(), discovery.ProviderDeps{}, Config{})
+	require.NoError(t, err)
+
+	get := func(token string) *gh.Client {
+		client := provider.getClient(token)
+		client.BaseURL, err = url.Parse(server.URL + "/")
+		require.NoError(t, err)
+
+		_, _, err = client.Repositories.Get(context.Background(), "ethpandaops", "fusaka-devnets")
+		require.NoError(t, err)
+
+		return client
+	}
+
+	first := get("first-token")
+	assert.Equal(t, "Bearer first-token", authorization)
+	assert.Same(t, first, get("first-token"), "the client is reused while the token is unchanged")
+
+	// A hot-reloaded token is used by the next run.
+	assert.NotSame(t, first, get("second-token"))
+	assert.Equal(t, "Bearer second-token", authorization)
+}
+
 func TestProvider_Discover(t *testing.T) {
 	// Create test cases table to cover different scenarios
 	testCases := []struct {
@@ -37,10 +84,7 @@ func TestProvider_Discover(t *testing.T) {
 		{
 			name: "successful discovery with standard networks",
 			config: discovery.Config{
-				GitHub: struct {
-					Repositories []discovery.GitHubRepositoryConfig `mapstructure:"repositories"`
-					Token        string                             `mapstructure:"token"`
-				}{
+				GitHub: discovery.GitHubConfig{
 					Repositories: []discovery.GitHubRepositoryConfig{
 						{
 							Name:       "ethpandaops/dencun-devnets",
@@ -68,10 +112,7 @@ func TestProvider_Discover(t *testing.T) {
 		{
 			name: "successful discovery with name prefix",
 			config: discovery.Config{
-				GitHub: struct {
-					Repositories []discovery.GitHubRepositoryConfig `mapstructure:"repositories"`
-					Token        string                             `mapstructure:"token"`
-				}{
+				GitHub: discovery.GitHubConfig{
 					Repositories: []discovery.GitHubRepositoryConfig{
 						{
 							Name:       "ethpandaops/dencun-devnets",
@@ -99,10 +140,7 @@ func TestProvider_Discover(t *testing.T) {
 		{
 			name: "different network statuses",
 			config: discovery.Config{
-				GitHub: struct {
-					Repositories []discovery.GitHubRepositoryConfig `mapstructure:"repositories"`
-					Token        string                             `mapstructure:"token"`
-				}{
+				GitHub: discovery.GitHubConfig{
 					Repositories: []discovery.GitHubRepositoryConfig{
 						{
 							Name:       "ethpandaops/pectra-devnets",
@@ -124,10 +162,7 @@ func TestProvider_Discover(t *testing.T) {
 		{
 			name: "invalid repository format",
 			config: discovery.Config{
-				GitHub: struct {
-					Repositories []discovery.GitHubRepositoryConfig `mapstructure:"repositories"`
-					Token        string                             `mapstructure:"token"`
-				}{
+				GitHub: discovery.GitHubConfig{
 					Repositories: []discovery.GitHubRepositoryConfig{
 						{
 							Name:       "invalid-repo-format",
@@ -143,10 +178,7 @@ func TestProvider_Discover(t *testing.T) {
 		{
 			name: "no repositories",
 			config: discovery.Config{
-				GitHub: struct {
-					Repositories []discovery.GitHubRepositoryConfig `mapstructure:"repositories"`
-					Token        string                             `mapstructure:"token"`
-				}{
+				GitHub: discovery.GitHubConfig{
 					Repositories: []discovery.GitHubRepositoryConfig{},
 					Token:        "dummy-token",
 				},
@@ -157,10 +189,7 @@ func TestProvider_Discover(t *testing.T) {
 		{
 			name: "no token",
 			config: discovery.Config{
-				GitHub: struct {
-					Repositories []discovery.GitHubRepositoryConfig `mapstructure:"repositories"`
-					Token        string                             `mapstructure:"token"`
-				}{
+				GitHub: discovery.GitHubConfig{
 					Repositories: []discovery.GitHubRepositoryConfig{
 						{
 							Name:       "ethpandaops/dencun-devnets",
@@ -180,7 +209,7 @@ func TestProvider_Discover(t *testing.T) {
 			log := logrus.New()
 			log.SetLevel(logrus.DebugLevel)
 
-			provider, err := NewProvider(log, nil)
+			provider, err := NewProvider(log, discovery.ProviderDeps{}, Config{})
 			require.NoError(t, err)
 
 			// Set up mock API if needed
@@ -213,9 +242,11 @@ func TestProvider_Discover(t *testing.T) {
 				return
 			}
 
-			// For invalid repo format, we shouldn't error but return empty networks
+			// For invalid repo format, the repository is reported as failed and no networks are returned
 			if tc.name == "invalid repository format" {
-				require.NoError(t, err)
+				var repoErrs discovery.RepositoryErrors
+				require.ErrorAs(t, err, &repoErrs)
+				assert.Contains(t, repoErrs, "invalid-repo-format")
 				assert.Empty(t, networks)
 
 				return
@@ -238,7 +269,7 @@ func TestProvider_Discover(t *testing.T) {
 					assert.Equal(t, "ethpandaops/dencun-devnets", network.Repository)
 					assert.Equal(t, "network-configs/"+network.Name, network.Path)
 					assert.Contains(t, network.URL, "github.com/ethpandaops/dencun-devnets/tree/main/network-configs/")
-					assert.WithinDuration(t, time.Now(), network.LastUpdated, 10*time.Second)
+					assert.Zero(t, network.LastUpdated, "LastUpdated is set by the discovery service")
 				}
 
 				// Additional checks for prefixed networks
@@ -251,7 +282,7 @@ func TestProvider_Discover(t *testing.T) {
 					assert.Equal(t, "ethpandaops/dencun-devnets", network.Repository)
 					assert.Equal(t, "network-configs/"+originalName, network.Path)
 					assert.Contains(t, network.URL, "github.com/ethpandaops/dencun-devnets/tree/main/network-configs/")
-					assert.WithinDuration(t, time.Now(), network.LastUpdated, 10*time.Second)
+					assert.Zero(t, network.LastUpdated, "LastUpdated is set by the discovery service")
 				}
 			}
 		})
@@ -262,7 +293,7 @@ func TestServiceURLs(t *testing.T) {
 	log := logrus.New()
 	log.SetLevel(logrus.DebugLevel)
 
-	provider, err := NewProvider(log, nil)
+	provider, err := NewProvider(log, discovery.ProviderDeps{}, Config{})
 	require.NoError(t, err)
 
 	// Create a test server that simulates valid/invalid service endpoints
@@ -317,24 +348,68 @@ func TestServiceURLs(t *testing.T) {
 			blobscanURL := specialServicePatterns["blobscan"]("mainnet.test-domain.com")
 			assert.Equal(t, "https://blobscan.com", blobscanURL)
 		})
+	})
 
-		t.Run("Devnet spec with prefix", func(t *testing.T) {
-			devnetDomain := "pectra-devnet-1.test-domain.com"
-			devnetSpecURL := specialServicePatterns["devnet_spec"](devnetDomain)
-			assert.Equal(t, "https://github.com/ethpandaops/pectra-devnets/tree/master/network-configs/devnet-1/metadata", devnetSpecURL)
+	t.Run("Ingress hosts", func(t *testing.T) {
+		urls := ingressServiceURLs([]discovery.Application{
+			{Name: "dora", Hosts: []string{"dora.test-domain.com"}},
+			{Name: "rpc", Hosts: []string{"RPC.test-domain.com", "rpc-2.test-domain.com"}},
+			{Name: "checkpointz", Hosts: []string{"checkpoint-sync.test-domain.com"}},
+			{Name: "tracoor"},
 		})
 
-		t.Run("Devnet spec with invalid domain", func(t *testing.T) {
-			emptySpecURL := specialServicePatterns["devnet_spec"]("")
-			assert.Equal(t, "", emptySpecURL)
-		})
+		assert.Equal(t, map[string]string{
+			"dora":            "https://dora.test-domain.com",
+			"json_rpc":        "https://RPC.test-domain.com",
+			"checkpoint_sync": "https://checkpoint-sync.test-domain.com",
+		}, urls)
+	})
+}
+
+// probeTransport answers service URL probes with 200 and records the hosts.
+type probeTransport struct {
+	mutex sync.Mutex
+	hosts []string
+}
+
+func (t *probeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	t.hosts = append(t.hosts, req.URL.Host)
+	t.mutex.Unlock()
+
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
 
-		t.Run("Devnet spec with non-prefixed domain", func(t *testing.T) {
-			nonPrefixDomain := "invalid-domain"
-			nonPrefixedSpecURL := specialServicePatterns["devnet_spec"](nonPrefixDomain)
-			expectedURL := "https://github.com/ethpandaops/invalid-devnets/tree/master/network-configs/domain/metadata"
-			assert.Equal(t, expectedURL, nonPrefixedSpecURL)
+func TestGetServiceURLs_Applications(t *testing.T) {
+	provider, err := NewProvider(logrus.New(), discovery.ProviderDeps{}, Config{})
+	require.NoError(t, err)
+
+	t.Run("Application without hosts", func(t *testing.T) {
+		transport := &probeTransport{}
+		provider.probeClient = &http.Client{Transport: transport}
+
+		services := provider.getServiceURLs(context.Background(), "devnet-1.example.com", []discovery.Application{{Name: "dora"}})
+
+		// Nothing is known from ingresses, so every common service is probed.
+		assert.Equal(t, "https://dora.devnet-1.example.com", services.Dora)
+		assert.Equal(t, "https://rpc.devnet-1.example.com", services.JSONRPC)
+		assert.Contains(t, transport.hosts, "faucet.devnet-1.example.com")
+	})
+
+	t.Run("Ingress hosts aren't probed", func(t *testing.T) {
+		transport := &probeTransport{}
+		provider.probeClient = &http.Client{Transport: transport}
+
+		services := provider.getServiceURLs(context.Background(), "devnet-1.example.com", []discovery.Application{
+			{Name: "rpc", Hosts: []string{"rpc.devnet-1.example.com"}},
 		})
+
+		assert.Equal(t, "https://rpc.devnet-1.example.com", services.JSONRPC)
+		assert.NotContains(t, transport.hosts, "rpc.devnet-1.example.com")
+
+		// The other services are still probed.
+		assert.Equal(t, "https://faucet.devnet-1.example.com", services.Faucet)
+		assert.Contains(t, transport.hosts, "faucet.devnet-1.example.com")
 	})
 }
 
@@ -366,53 +441,21 @@ func (t *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 func mockGitHubAPI(t *testing.T) *httptest.Server {
 	t.Helper()
 
-	mux := http.NewServeMux()
-
-	// Mock repository contents
-	mux.HandleFunc("/ethpandaops/dencun-devnets/contents", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`[
-			{"name": ".editorconfig", "type": "file", "html_url": "https://github.com/ethpandaops/dencun-devnets/blob/main/.editorconfig"},
-			{"name": ".gitattributes", "type": "file", "html_url": "https://github.com/ethpandaops/dencun-devnets/blob/main/.gitattributes"},
-			{"name": ".github", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/.github"},
-			{"name": ".gitignore", "type": "file", "html_url": "https://github.com/ethpandaops/dencun-devnets/blob/main/.gitignore"},
-			{"name": "README.md", "type": "file", "html_url": "https://github.com/ethpandaops/dencun-devnets/blob/main/README.md"},
-			{"name": "network-configs", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs"},
-			{"name": "kubernetes", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/kubernetes"}
-		]`))
-	})
-
-	// Mock network-configs contents
-	mux.HandleFunc("/ethpandaops/dencun-devnets/contents/network-configs", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`[
-			{"name": "devnet-10", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/devnet-10"},
-			{"name": "devnet-11", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/devnet-11"},
-			{"name": "devnet-12", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/devnet-12"},
-			{"name": "devnet-4", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/devnet-4"},
-			{"name": "devnet-5", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/devnet-5"},
-			{"name": "gsf-1", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/gsf-1"},
-			{"name": "gsf-2", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/gsf-2"},
-			{"name": "msf-1", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/msf-1"},
-			{"name": "sepolia-sf1", "type": "dir", "html_url": "https://github.com/ethpandaops/dencun-devnets/tree/main/network-configs/sepolia-sf1"}
-		]`))
-	})
+	files := map[string]string{
+		".editorconfig": "root = true",
+		"README.md":     "dencun devnets",
+	}
 
-	// Mock kubernetes directory paths for all the networks to mark them as active
+	// Mark all the networks as active with a kubernetes directory
 	networks := []string{"devnet-10", "devnet-11", "devnet-12", "devnet-4", "devnet-5", "gsf-1", "gsf-2", "msf-1", "sepolia-sf1"}
 	for _, network := range networks {
-		path := fmt.Sprintf("/ethpandaops/dencun-devnets/contents/kubernetes/%s", network)
-		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`[
-				{"name": "some-file.yaml", "type": "file", "html_url": "https://github.com/ethpandaops/dencun-devnets/blob/main/kubernetes/` + network + `/some-file.yaml"}
-			]`))
-		})
+		files["network-configs/"+network+"/README.md"] = network
+		files["kubernetes/"+network+"/some-file.yaml"] = "kind: " + network
 	}
 
+	mux := http.NewServeMux()
+	newFakeRepo("ethpandaops/dencun-devnets", files).register(mux)
+
 	// Return test server
 	server := httptest.NewServer(mux)
 
@@ -422,69 +465,67 @@ func mockGitHubAPI(t *testing.T) *httptest.Server {
 // Mock GitHub API with status checking.
 func mockGitHubAPIWithStatus(t *testing.T) *httptest.Server {
 	t.Helper()
-	mux := http.NewServeMux()
 
-	// Mock repository contents
-	mux.HandleFunc("/ethpandaops/pectra-devnets/contents", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`[
-			{"name": ".github", "type": "dir", "html_url": "https://github.com/ethpandaops/pectra-devnets/tree/main/.github"},
-			{"name": "network-configs", "type": "dir", "html_url": "https://github.com/ethpandaops/pectra-devnets/tree/main/network-configs"},
-			{"name": "kubernetes", "type": "dir", "html_url": "https://github.com/ethpandaops/pectra-devnets/tree/main/kubernetes"},
-			{"name": "kubernetes-archive", "type": "dir", "html_url": "https://github.com/ethpandaops/pectra-devnets/tree/main/kubernetes-archive"}
-		]`))
-	})
+	files := map[string]string{
+		".github/CODEOWNERS":                        "*",
+		"network-configs/devnet-active/README.md":   "active",
+		"network-configs/devnet-inactive/README.md": "inactive",
+		"network-configs/devnet-unknown/README.md":  "unknown",
+		// devnet-active exists in kubernetes, devnet-inactive in kubernetes-archive
+		"kubernetes/devnet-active/some-file.yaml":           "kind: active",
+		"kubernetes-archive/devnet-inactive/some-file.yaml": "kind: inactive",
+	}
 
-	// Mock network-configs contents
-	mux.HandleFunc("/ethpandaops/pectra-devnets/contents/network-configs", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`[
-			{"name": "devnet-active", "type": "dir", "html_url": "https://github.com/ethpandaops/pectra-devnets/tree/main/network-configs/devnet-active"},
-			{"name": "devnet-inactive", "type": "dir", "html_url": "https://github.com/ethpandaops/pectra-devnets/tree/main/network-configs/devnet-inactive"},
-			{"name": "devnet-unknown", "type": "dir", "html_url": "https://github.com/ethpandaops/pectra-devnets/tree/main/network-configs/devnet-unknown"}
-		]`))
-	})
+	mux := http.NewServeMux()
+	newFakeRepo("ethpandaops/pectra-devnets", files).register(mux)
 
-	// Mock kubernetes directory - devnet-active exists here
-	mux.HandleFunc("/ethpandaops/pectra-devnets/contents/kubernetes/devnet-active", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`[
-			{"name": "some-file.yaml", "type": "file", "html_url": "https://github.com/ethpandaops/pectra-devnets/blob/main/kubernetes/devnet-active/some-file.yaml"}
-		]`))
-	})
+	// Return test server
+	server := httptest.NewServer(mux)
 
-	// Mock kubernetes directory - devnet-inactive does not exist here
-	mux.HandleFunc("/ethpandaops/pectra-devnets/contents/kubernetes/devnet-inactive", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, _ = w.Write([]byte(`{"message": "Not Found", "documentation_url": "https://docs.github.com"}`))
-	})
+	return server
+}
 
-	// Mock kubernetes directory - devnet-unknown does not exist here
-	mux.HandleFunc("/ethpandaops/pectra-devnets/contents/kubernetes/devnet-unknown", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, _ = w.Write([]byte(`{"message": "Not Found", "documentation_url": "https://docs.github.com"}`))
-	})
+func TestProvider_ListRepositories(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/ethpandaops/repos", func(w http.ResponseWriter, r *http.Request) {
+		// Two pages, linked as by the GitHub API.
+		if r.URL.Query().Get("page") == "2" {
+			writeTestJSON(w, []map[string]any{{"full_name": "ethpandaops/pectra-devnets", "archived": true}})
 
-	// Mock kubernetes-archive directory - devnet-inactive exists here
-	mux.HandleFunc("/ethpandaops/pectra-devnets/contents/kubernetes-archive/devnet-inactive", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`[
-			{"name": "some-file.yaml", "type": "file", "html_url": "https://github.com/ethpandaops/pectra-devnets/blob/main/kubernetes-archive/devnet-inactive/some-file.yaml"}
-		]`))
-	})
+			return
+		}
 
-	// Mock kubernetes-archive directory - devnet-unknown does not exist here
-	mux.HandleFunc("/ethpandaops/pectra-devnets/contents/kubernetes-archive/devnet-unknown", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, _ = w.Write([]byte(`{"message": "Not Found", "documentation_url": "https://docs.github.com"}`))
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/ethpandaops/repos?page=2>; rel="next"`, r.Host))
+		writeTestJSON(w, []map[string]any{{
+			"full_name":   "ethpandaops/fusaka-devnets",
+			"description": "Fusaka devnets",
+			"homepage":    "https://fusaka.ethpandaops.io",
+			"html_url":    "https://github.com/ethpandaops/fusaka-devnets",
+			"topics":      []string{"devnet"},
+		}})
 	})
 
-	// Return test server
 	server := httptest.NewServer(mux)
+	defer server.Close()
 
-	return server
+	provider, err := NewProvider(logrus.New(), discovery.ProviderDeps{}, Config{})
+	require.NoError(t, err)
+
+	provider.githubClient = gh.NewClient(nil)
+	provider.githubClient.BaseURL, err = url.Parse(server.URL + "/")
+	require.NoError(t, err)
+
+	repos, err := provider.ListRepositories(context.Background(), discovery.Config{}, "ethpandaops")
+	require.NoError(t, err)
+
+	assert.Equal(t, []discovery.SourceRepository{
+		{
+			Name:        "ethpandaops/fusaka-devnets",
+			Description: "Fusaka devnets",
+			Homepage:    "https://fusaka.ethpandaops.io",
+			URL:         "https://github.com/ethpandaops/fusaka-devnets",
+			Topics:      []string{"devnet"},
+		},
+		{Name: "ethpandaops/pectra-devnets", Archived: true},
+	}, repos)
 }
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
 )
@@ -50,35 +49,14 @@ var specialServicePatterns = map[string]func(string) string{
 
 		return ""
 	},
-	"devnet_spec": func(domain string) string {
-		parts := strings.Split(domain, ".")
-		if len(parts) == 0 {
-			return ""
-		}
-
-		domainPrefix := parts[0]
-
-		// Extract prefix (e.g., "pectra", "dencun") and network name.
-		prefixParts := strings.SplitN(domainPrefix, "-", 2)
-		if len(prefixParts) != 2 {
-			return ""
-		}
-
-		prefix := prefixParts[0]      // e.g., "pectra", "dencun".
-		networkName := prefixParts[1] // e.g., "msf-1", "devnet-6".
-
-		// Construct the URL using the extracted prefix and network name.
-		return fmt.Sprintf("https://github.com/ethpandaops/%s-devnets/tree/master/network-configs/%s/metadata",
-			prefix, networkName)
-	},
 }
 
-// getServiceURLs constructs and validates service URLs for a network.
-func (p *Provider) getServiceURLs(ctx context.Context, domain string) *discovery.ServiceURLs {
+// getServiceURLs constructs and validates service URLs for a network. Common
+// services found among the ingress hosts of applications are taken from
+// there, the others are guessed from the domain and probed.
+func (p *Provider) getServiceURLs(ctx context.Context, domain string, applications []discovery.Application) *discovery.ServiceURLs {
 	services := &discovery.ServiceURLs{}
-	client := &http.Client{
-		Timeout: 2 * time.Second, // Short timeout for quick checks.
-	}
+	client := p.probeClient
 
 	p.log.WithField("domain", domain).Debug("Checking service URLs")
 
@@ -94,8 +72,17 @@ func (p *Provider) getServiceURLs(ctx context.Context, domain string) *discovery
 		numChecks int
 	)
 
-	// Start goroutines for common services
+	ingressURLs := ingressServiceURLs(applications)
+	for serviceKey, url := range ingressURLs {
+		setServiceURL(services, serviceKey, url)
+	}
+
+	// Start goroutines for common services not found among the ingress hosts
 	for serviceKey, pattern := range servicePatterns {
+		if _, ok := ingressURLs[serviceKey]; ok {
+			continue
+		}
+
 		numChecks++
 
 		go func(key, pattern string) {
@@ -112,11 +99,6 @@ func (p *Provider) getServiceURLs(ctx context.Context, domain string) *discovery
 
 	// Start goroutines for special services that need validation
 	for serviceKey, patternFunc := range specialServicePatterns {
-		// Skip static URLs that don't need validation.
-		if serviceKey == "devnet_spec" {
-			continue
-		}
-
 		url := patternFunc(domain)
 		if url != "" {
 			numChecks++
@@ -143,64 +125,74 @@ func (p *Provider) getServiceURLs(ctx context.Context, domain string) *discovery
 			"valid":   result.valid,
 		}).Debug("Checked service URL")
 
+		observeServiceURLProbe(result.serviceKey, result.valid)
+
 		if result.valid {
-			switch result.serviceKey {
-			case "blobscan":
-				services.Blobscan = result.url
-			case "faucet":
-				services.Faucet = result.url
-			case "json_rpc":
-				services.JSONRPC = result.url
-			case "beacon_rpc":
-				services.BeaconRPC = result.url
-			case "explorer":
-				services.Explorer = result.url
-			case "forkmon":
-				services.Forkmon = result.url
-			case "forky":
-				services.Forky = result.url
-			case "assertoor":
-				services.Assertoor = result.url
-			case "dora":
-				services.Dora = result.url
-			case "checkpoint_sync":
-				services.CheckpointSync = result.url
-			case "ethstats":
-				services.Ethstats = result.url
-			case "beacon_explorer":
-				services.BeaconExplorer = result.url
-			case "tracoor":
-				services.Tracoor = result.url
-			case "syncoor":
-				services.Syncoor = result.url
-			case "spamoor":
-				services.Spamoor = result.url
-			case "buildoor":
-				services.Buildoor = result.url
-			}
+			setServiceURL(services, result.serviceKey, result.url)
 		}
 	}
 
-	// Add static services that don't need validation
-	for serviceKey, patternFunc := range specialServicePatterns {
-		if serviceKey == "devnet_spec" || serviceKey == "blobscan" {
-			url := patternFunc(domain)
-			if url != "" {
-				switch serviceKey {
-				case "devnet_spec":
-					services.DevnetSpec = url
-				}
+	return services
+}
 
-				p.log.WithFields(map[string]any{
-					"service": serviceKey,
-					"url":     url,
-					"valid":   true, // Assumed valid for static URLs
-				}).Debug("Added static service URL without validation")
+// setServiceURL sets the service URL of serviceKey.
+func setServiceURL(services *discovery.ServiceURLs, key, url string) {
+	switch key {
+	case "blobscan":
+		services.Blobscan = url
+	case "faucet":
+		services.Faucet = url
+	case "json_rpc":
+		services.JSONRPC = url
+	case "beacon_rpc":
+		services.BeaconRPC = url
+	case "explorer":
+		services.Explorer = url
+	case "forkmon":
+		services.Forkmon = url
+	case "forky":
+		services.Forky = url
+	case "assertoor":
+		services.Assertoor = url
+	case "dora":
+		services.Dora = url
+	case "checkpoint_sync":
+		services.CheckpointSync = url
+	case "ethstats":
+		services.Ethstats = url
+	case "beacon_explorer":
+		services.BeaconExplorer = url
+	case "tracoor":
+		services.Tracoor = url
+	case "syncoor":
+		services.Syncoor = url
+	case "spamoor":
+		services.Spamoor = url
+	case "buildoor":
+		services.Buildoor = url
+	}
+}
+
+// ingressServiceURLs returns the URLs of the common services among the
+// ingress hosts of applications, recognized by their first label, e.g.
+// dora.<domain> is dora.
+func ingressServiceURLs(applications []discovery.Application) map[string]string {
+	urls := make(map[string]string)
+
+	for _, app := range applications {
+		for _, host := range app.Hosts {
+			label, _, _ := strings.Cut(host, ".")
+
+			for serviceKey, pattern := range servicePatterns {
+				patternLabel, _, _ := strings.Cut(strings.TrimPrefix(pattern, "https://"), ".")
+				if _, taken := urls[serviceKey]; !taken && strings.EqualFold(label, patternLabel) {
+					urls[serviceKey] = "https://" + host
+				}
 			}
 		}
 	}
 
-	return services
+	return urls
 }
 
 // isURLValid checks if a URL is reachable.
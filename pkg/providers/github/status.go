@@ -5,70 +5,81 @@ import (
 	"path"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
-	gh "github.com/google/go-github/v53/github"
 	"github.com/sirupsen/logrus"
 )
 
-// determineNetworkStatus determines if a network is active, inactive, or unknown.
+// determineNetworkStatus determines if a network is active, inactive, or
+// unknown, and reads the values of active networks.
 func (p *Provider) determineNetworkStatus(
 	ctx context.Context,
-	client *gh.Client,
-	owner, repo, networkName string,
-) (string, []string, string) {
+	reader *repoReader,
+	networkName string,
+) (string, *networkValues) {
 	var (
-		status      = unknown
-		configFiles []string
-		domain      string
+		status = unknown
+		values = &networkValues{}
 	)
 
 	// Check if network exists in kubernetes directory (active).
-	kubePath := path.Join(kubernetesDir, networkName)
-
-	_, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, kubePath, nil)
-	if err == nil || (resp != nil && resp.StatusCode != 404) {
+	if reader.snapshot.isDir(reader.networkPath(reader.paths.Active, networkName)) {
 		status = active
 
 		// For active networks, try to get config values
-		configFiles, domain = p.getNetworkConfigs(ctx, client, owner, repo, kubePath, networkName)
-	} else {
-		// Check if network exists in kubernetes-archive directory (inactive).
-		archivePath := path.Join(kubernetesArchiveDir, networkName)
-
-		_, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, archivePath, nil)
-		if err == nil || (resp != nil && resp.StatusCode != 404) {
-			status = inactive
-		}
+		values = p.getNetworkConfigs(ctx, reader, networkName)
+	} else if reader.snapshot.isDir(reader.networkPath(reader.paths.Archived, networkName)) {
+		// Network exists in kubernetes-archive directory (inactive).
+		status = inactive
 	}
 
-	return status, configFiles, domain
+	return status, values
 }
 
 // getNetworkDetails fetches configuration details and images for a network.
 func (p *Provider) getNetworkDetails(
 	ctx context.Context,
-	client *gh.Client,
-	owner, repo, networkName string,
-) (status string, configFiles []string, domain string, images *discovery.Images, hiveURL string, selfHostedDNS bool) {
-	// Get basic network status, configs, and domain
-	status, configFiles, domain = p.determineNetworkStatus(ctx, client, owner, repo, networkName)
+	reader *repoReader,
+	networkName string,
+) (status string, values *networkValues, images *discovery.Images, hiveURL string, selfHostedDNS bool) {
+	// Get basic network status, configs, domain and applications
+	status, values = p.determineNetworkStatus(ctx, reader, networkName)
 
 	// For active networks, try to get images + hive information.
 	if status == active {
-		images, _ = p.getImages(ctx, client, owner, repo, networkName)
+		images, _ = p.getImages(ctx, reader, networkName)
 	}
 
-	var err error
+	hiveURL = p.getHiveURL(ctx, reader, networkName)
+
+	// Check if network uses a self-hosted DNS server
+	selfHostedDNS = p.checkSelfHostedDNS(reader, networkName)
+
+	return status, values, images, hiveURL, selfHostedDNS
+}
+
+// getHiveURL returns the hive URL of a network if hive is available. Hive
+// checks are low priority, so the last result is reused while the GitHub API
+// budget is low.
+func (p *Provider) getHiveURL(ctx context.Context, reader *repoReader, networkName string) string {
+	key := path.Join(reader.owner, reader.repo, networkName)
+
+	if p.rateLimits.Low() {
+		p.cacheMutex.Lock()
+		defer p.cacheMutex.Unlock()
 
-	hiveURL, err = p.checkHiveAvailability(ctx, owner, repo, networkName)
+		return p.hiveURLs[key]
+	}
+
+	hiveURL, err := p.checkHiveAvailability(ctx, reader.owner, reader.repo, networkName)
 	if err != nil {
 		p.log.WithFields(logrus.Fields{
-			"repo":    repo,
+			"repo":    reader.repo,
 			"network": networkName,
 		}).Debug("hive is not available for network")
 	}
 
-	// Check if network uses a self-hosted DNS server
-	selfHostedDNS = p.checkSelfHostedDNS(ctx, client, owner, repo, networkName)
+	p.cacheMutex.Lock()
+	p.hiveURLs[key] = hiveURL
+	p.cacheMutex.Unlock()
 
-	return status, configFiles, domain, images, hiveURL, selfHostedDNS
+	return hiveURL
 }
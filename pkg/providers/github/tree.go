@@ -0,0 +1,268 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	gh "github.com/google/go-github/v53/github"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// snapshotDirs returns the top-level directories discovery reads in a
+// repository with the given layout. If the recursive tree of a repository is
+// too large for a single response, only these are fetched.
+func snapshotDirs(paths discovery.RepositoryPaths) []string {
+	var dirs []string
+
+	for _, p := range []string{paths.NetworkConfigs, paths.Active, paths.Archived, paths.ValuesFile, paths.ImagesFile, paths.Inventories} {
+		dir, _, _ := strings.Cut(p, "/")
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// treeEntry is a file or directory in a repository snapshot.
+type treeEntry struct {
+	typ string
+	sha string
+}
+
+// repoSnapshot is the tree of a repository at a pinned commit. Existence checks
+// are resolved from it instead of an API call per path.
+type repoSnapshot struct {
+	// ref is the branch or tag the commit was resolved from.
+	ref       string
+	commitSHA string
+	entries   map[string]treeEntry
+}
+
+// isDir returns true if p is a directory in the snapshot.
+func (s *repoSnapshot) isDir(p string) bool {
+	return s.entries[p].typ == "tree"
+}
+
+// isFile returns true if p is a file in the snapshot.
+func (s *repoSnapshot) isFile(p string) bool {
+	return s.entries[p].typ == "blob"
+}
+
+// subdirs returns the sorted names of the directories directly inside dir.
+func (s *repoSnapshot) subdirs(dir string) []string {
+	prefix := dir + "/"
+
+	var names []string
+
+	for p, entry := range s.entries {
+		name, ok := strings.CutPrefix(p, prefix)
+		if ok && entry.typ == "tree" && !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// add adds the entries of tree, rooted at dir, to the snapshot.
+func (s *repoSnapshot) add(dir string, tree *gh.Tree) {
+	for _, entry := range tree.Entries {
+		s.entries[path.Join(dir, entry.GetPath())] = treeEntry{
+			typ: entry.GetType(),
+			sha: entry.GetSHA(),
+		}
+	}
+}
+
+// repoCache is what the provider keeps of a repository between runs.
+type repoCache struct {
+	snapshot *repoSnapshot
+	// blobs holds the contents of the files read in the last run by blob SHA.
+	blobs map[string][]byte
+}
+
+// repoReader reads files from a repository snapshot during one run, fetching
+// only blobs that aren't cached from the last run.
+type repoReader struct {
+	client   *gh.Client
+	owner    string
+	repo     string
+	paths    discovery.RepositoryPaths
+	snapshot *repoSnapshot
+	cached   map[string][]byte
+
+	// Networks are read concurrently.
+	mutex   sync.Mutex
+	blobs   map[string][]byte
+	fetched int
+}
+
+// newRepoReader creates a reader of snapshot, a repository with the given
+// layout, reusing the blobs of cache.
+func newRepoReader(
+	client *gh.Client,
+	owner, repo string,
+	paths discovery.RepositoryPaths,
+	snapshot *repoSnapshot,
+	cache *repoCache,
+) *repoReader {
+	cached := make(map[string][]byte)
+	if cache != nil {
+		cached = cache.blobs
+	}
+
+	return &repoReader{
+		client:   client,
+		owner:    owner,
+		repo:     repo,
+		paths:    paths,
+		snapshot: snapshot,
+		cached:   cached,
+		blobs:    make(map[string][]byte),
+	}
+}
+
+// readFile returns the content of the file at p. It returns an error wrapping
+// fs.ErrNotExist if there is no such file.
+func (r *repoReader) readFile(ctx context.Context, p string) (string, error) {
+	entry, ok := r.snapshot.entries[p]
+	if !ok || entry.typ != "blob" {
+		return "", fmt.Errorf("%s: %w", p, fs.ErrNotExist)
+	}
+
+	r.mutex.Lock()
+	content, ok := r.blobs[entry.sha]
+	r.mutex.Unlock()
+
+	if ok {
+		return string(content), nil
+	}
+
+	content, ok = r.cached[entry.sha]
+	if ok {
+		fileReads.WithLabelValues("cache").Inc()
+	} else {
+		var err error
+
+		content, _, err = r.client.Git.GetBlobRaw(ctx, r.owner, r.repo, entry.sha)
+		if err != nil {
+			return "", fmt.Errorf("failed to get blob of %s: %w", p, err)
+		}
+
+		fileReads.WithLabelValues("api").Inc()
+
+		r.mutex.Lock()
+		r.fetched++
+		r.mutex.Unlock()
+	}
+
+	r.mutex.Lock()
+	r.blobs[entry.sha] = content
+	r.mutex.Unlock()
+
+	return string(content), nil
+}
+
+// networkPath returns a path template of the repository layout with the
+// network name filled in.
+func (r *repoReader) networkPath(template, networkName string) string {
+	return discovery.ForNetwork(template, networkName)
+}
+
+// cache returns what to keep of this run for the next one: the snapshot and
+// the blobs that were read.
+func (r *repoReader) cache() *repoCache {
+	return &repoCache{snapshot: r.snapshot, blobs: r.blobs}
+}
+
+// loadSnapshot resolves the commit of ref, or of the default branch if ref is
+// empty, and fetches its tree, reusing previous if the commit hasn't changed.
+// dirs are the top-level directories to fetch if the tree is too large.
+func loadSnapshot(
+	ctx context.Context,
+	client *gh.Client,
+	owner, repo, ref string,
+	dirs []string,
+	previous *repoSnapshot,
+) (*repoSnapshot, error) {
+	if ref == "" {
+		repository, _, err := client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository: %w", err)
+		}
+
+		ref = repository.GetDefaultBranch()
+	}
+
+	lastSHA := ""
+	if previous != nil && previous.ref == ref {
+		lastSHA = previous.commitSHA
+	}
+
+	// GitHub answers 304 Not Modified if the ref still points at lastSHA, which
+	// doesn't count against the rate limit.
+	sha, resp, err := client.Repositories.GetCommitSHA1(ctx, owner, repo, ref, lastSHA)
+	if err != nil {
+		if lastSHA != "" && resp != nil && resp.StatusCode == http.StatusNotModified {
+			return previous, nil
+		}
+
+		return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	snapshot := &repoSnapshot{
+		ref:       ref,
+		commitSHA: sha,
+		entries:   make(map[string]treeEntry),
+	}
+
+	tree, _, err := client.Git.GetTree(ctx, owner, repo, sha, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree at %s: %w", sha, err)
+	}
+
+	if !tree.GetTruncated() {
+		snapshot.add("", tree)
+
+		return snapshot, nil
+	}
+
+	// The repository is too large for one recursive tree, fetch only the
+	// directories discovery reads.
+	root, _, err := client.Git.GetTree(ctx, owner, repo, sha, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree at %s: %w", sha, err)
+	}
+
+	snapshot.add("", root)
+
+	for _, dir := range dirs {
+		entry, ok := snapshot.entries[dir]
+		if !ok || entry.typ != "tree" {
+			continue
+		}
+
+		subtree, _, err := client.Git.GetTree(ctx, owner, repo, entry.sha, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tree of %s: %w", dir, err)
+		}
+
+		if subtree.GetTruncated() {
+			return nil, fmt.Errorf("tree of %s is too large", dir)
+		}
+
+		snapshot.add(dir, subtree)
+	}
+
+	return snapshot, nil
+}
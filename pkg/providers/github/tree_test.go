@@ -0,0 +1,372 @@
+package github
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // Git object IDs, not security.
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	gh "github.com/google/go-github/v53/github"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// fakeRepo serves a repository through the Git Trees and Blobs APIs, as
+// rewritten by mockTransport.
+type fakeRepo struct {
+	name string
+
+	mutex sync.Mutex
+	files map[string]string
+	// truncated makes recursive trees of the root report truncation.
+	truncated bool
+
+	treeCalls int
+	blobCalls int
+}
+
+// newFakeRepo creates a repository "owner/repo" with the given files.
+func newFakeRepo(name string, files map[string]string) *fakeRepo {
+	return &fakeRepo{name: name, files: files}
+}
+
+// setFile adds or changes a file, which moves the branch to a new commit.
+func (f *fakeRepo) setFile(p, content string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.files[p] = content
+}
+
+// calls returns the number of tree and blob requests served.
+func (f *fakeRepo) calls() (trees, blobs int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.treeCalls, f.blobCalls
+}
+
+func gitSHA(s string) string {
+	sum := sha1.Sum([]byte(s)) //nolint:gosec // Git object IDs, not security.
+
+	return hex.EncodeToString(sum[:])
+}
+
+// tree returns the entries below dir, recursively or not, and the tree SHA of
+// every directory.
+func (f *fakeRepo) tree(dir string, recursive bool) []*gh.TreeEntry {
+	entries := make(map[string]*gh.TreeEntry)
+
+	for _, p := range slices.Sorted(maps.Keys(f.files)) {
+		rel := p
+		if dir != "" {
+			var ok bool
+			if rel, ok = strings.CutPrefix(p, dir+"/"); !ok {
+				continue
+			}
+		}
+
+		parts := strings.Split(rel, "/")
+		if !recursive && len(parts) > 1 {
+			parts = parts[:1]
+		}
+
+		for i := range parts {
+			entryPath := strings.Join(parts[:i+1], "/")
+			if i == len(parts)-1 && entryPath == rel {
+				entries[entryPath] = &gh.TreeEntry{Path: gh.String(entryPath), Type: gh.String("blob"), SHA: gh.String(gitSHA(f.files[p]))}
+			} else if _, ok := entries[entryPath]; !ok {
+				entries[entryPath] = &gh.TreeEntry{Path: gh.String(entryPath), Type: gh.String("tree"), SHA: gh.String(gitSHA("tree:" + path.Join(dir, entryPath)))}
+			}
+		}
+	}
+
+	result := make([]*gh.TreeEntry, 0, len(entries))
+	for _, p := range slices.Sorted(maps.Keys(entries)) {
+		result = append(result, entries[p])
+	}
+
+	return result
+}
+
+// commitSHA derives the head commit from the content of all files.
+func (f *fakeRepo) commitSHA() string {
+	var b strings.Builder
+	for _, p := range slices.Sorted(maps.Keys(f.files)) {
+		b.WriteString(p + "\x00" + f.files[p] + "\x00")
+	}
+
+	return gitSHA(b.String())
+}
+
+// register adds the repository's endpoints to mux.
+func (f *fakeRepo) register(mux *http.ServeMux) {
+	base := "/" + f.name
+
+	mux.HandleFunc(base, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]any{"full_name": f.name, "default_branch": "main"})
+	})
+
+	// Every branch and tag points at the head commit.
+	mux.HandleFunc(base+"/commits/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		sha := f.commitSHA()
+		f.mutex.Unlock()
+
+		if r.Header.Get("If-None-Match") == `"`+sha+`"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(sha))
+	})
+
+	mux.HandleFunc(base+"/git/trees/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		f.treeCalls++
+
+		recursive := r.URL.Query().Get("recursive") == "1"
+		sha := r.PathValue("sha")
+
+		if sha == f.commitSHA() {
+			writeTestJSON(w, gh.Tree{SHA: gh.String(sha), Entries: f.tree("", recursive), Truncated: gh.Bool(recursive && f.truncated)})
+
+			return
+		}
+
+		for _, entry := range f.tree("", true) {
+			if entry.GetType() == "tree" && entry.GetSHA() == sha {
+				writeTestJSON(w, gh.Tree{SHA: gh.String(sha), Entries: f.tree(entry.GetPath(), recursive), Truncated: gh.Bool(false)})
+
+				return
+			}
+		}
+
+		http.NotFound(w, r)
+	})
+
+	mux.HandleFunc(base+"/git/blobs/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		f.blobCalls++
+
+		for _, content := range f.files {
+			if gitSHA(content) == r.PathValue("sha") {
+				_, _ = w.Write([]byte(content))
+
+				return
+			}
+		}
+
+		http.NotFound(w, r)
+	})
+}
+
+func writeTestJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// newFakeRepoProvider returns a provider backed by repo.
+func newFakeRepoProvider(t *testing.T, repo *fakeRepo) *Provider {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	repo.register(mux)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider, err := NewProvider(logrus.New(), discovery.ProviderDeps{}, Config{})
+	require.NoError(t, err)
+
+	provider.githubClient = gh.NewClient(&http.Client{Transport: &mockTransport{URL: server.URL}})
+
+	return provider
+}
+
+func testRepoConfig(name string) discovery.Config {
+	config := discovery.Config{}
+	config.GitHub.Repositories = []discovery.GitHubRepositoryConfig{{Name: name}}
+
+	return config
+}
+
+func devnetFiles() map[string]string {
+	return map[string]string{
+		"README.md": "devnets",
+		"network-configs/devnet-1/metadata/config.yaml":           "DEPOSIT_CHAIN_ID: 7001\n",
+		"network-configs/devnet-2/metadata/config.yaml":           "DEPOSIT_CHAIN_ID: 7002\n",
+		"kubernetes/devnet-1/config/values.yaml":                  "config:\n  files: []\n",
+		"kubernetes-archive/devnet-2/config/values.yaml":          "config:\n  files: []\n",
+		"ansible/inventories/devnet-1/group_vars/dns_server.yaml": "dns: true\n",
+	}
+}
+
+func TestRepoSnapshot(t *testing.T) {
+	repo := newFakeRepo("ethpandaops/test-devnets", devnetFiles())
+
+	snapshot := &repoSnapshot{entries: make(map[string]treeEntry)}
+	snapshot.add("", &gh.Tree{Entries: repo.tree("", true)})
+
+	assert.Equal(t, []string{"devnet-1", "devnet-2"}, snapshot.subdirs(discovery.DefaultNetworkConfigsPath))
+	assert.True(t, snapshot.isDir("kubernetes/devnet-1"))
+	assert.False(t, snapshot.isDir("kubernetes/devnet-2"))
+	assert.True(t, snapshot.isFile("ansible/inventories/devnet-1/group_vars/dns_server.yaml"))
+	assert.False(t, snapshot.isFile("ansible/inventories/devnet-1"))
+}
+
+func TestDiscover_FetchesOnlyChangedFiles(t *testing.T) {
+	repo := newFakeRepo("ethpandaops/test-devnets", devnetFiles())
+	provider := newFakeRepoProvider(t, repo)
+	config := testRepoConfig(repo.name)
+	ctx := context.Background()
+
+	networks, err := provider.Discover(ctx, config)
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+
+	assert.Equal(t, "active", networks["devnet-1"].Status)
+	assert.Equal(t, uint64(7001), networks["devnet-1"].ChainID)
+	assert.True(t, networks["devnet-1"].SelfHostedDNS)
+	assert.Equal(t, "https://github.com/ethpandaops/test-devnets/tree/main/network-configs/devnet-1", networks["devnet-1"].URL)
+	assert.Equal(t, "inactive", networks["devnet-2"].Status)
+	assert.False(t, networks["devnet-2"].SelfHostedDNS)
+
+	trees, blobs := repo.calls()
+	assert.Equal(t, 1, trees, "one recursive tree per repository")
+	assert.Equal(t, 2, blobs, "values.yaml and config.yaml of the active network")
+
+	// Nothing pushed: the tree and files are reused.
+	again, err := provider.Discover(ctx, config)
+	require.NoError(t, err)
+	assert.Equal(t, networks, again)
+
+	trees, blobs = repo.calls()
+	assert.Equal(t, 1, trees)
+	assert.Equal(t, 2, blobs)
+
+	// A push changing one file only fetches that file.
+	repo.setFile("network-configs/devnet-1/metadata/config.yaml", "DEPOSIT_CHAIN_ID: 7010\n")
+
+	changed, err := provider.Discover(ctx, config)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7010), changed["devnet-1"].ChainID)
+
+	trees, blobs = repo.calls()
+	assert.Equal(t, 2, trees)
+	assert.Equal(t, 3, blobs)
+}
+
+func TestDiscoverWithWarnings(t *testing.T) {
+	files := devnetFiles()
+	files["kubernetes/devnet-1/config/values.yaml"] = "config: [\n"
+	files["network-configs/devnet-1/metadata/genesis.json"] = "{"
+
+	repo := newFakeRepo("ethpandaops/test-devnets", files)
+	provider := newFakeRepoProvider(t, repo)
+
+	networks, warnings, err := provider.DiscoverWithWarnings(context.Background(), testRepoConfig(repo.name), nil)
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+	require.Len(t, warnings, 2)
+
+	warned := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		assert.Equal(t, repo.name, warning.Repository)
+		assert.Equal(t, "devnet-1", warning.Network)
+		assert.NotEmpty(t, warning.Message)
+
+		warned = append(warned, warning.File)
+	}
+
+	assert.ElementsMatch(t, []string{"kubernetes/devnet-1/config/values.yaml", "network-configs/devnet-1/metadata"}, warned)
+}
+
+func TestDiscover_TruncatedTree(t *testing.T) {
+	files := devnetFiles()
+	files["docs/huge.md"] = "not needed"
+
+	repo := newFakeRepo("ethpandaops/test-devnets", files)
+	repo.truncated = true
+
+	provider := newFakeRepoProvider(t, repo)
+
+	networks, err := provider.Discover(context.Background(), testRepoConfig(repo.name))
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+	assert.Equal(t, "active", networks["devnet-1"].Status)
+	assert.True(t, networks["devnet-1"].SelfHostedDNS)
+
+	// The truncated tree, the root and the four directories discovery reads.
+	trees, _ := repo.calls()
+	assert.Equal(t, 6, trees)
+}
+
+func TestDiscover_MissingNetworkConfigs(t *testing.T) {
+	repo := newFakeRepo("ethpandaops/empty", map[string]string{"README.md": "empty"})
+	provider := newFakeRepoProvider(t, repo)
+
+	_, err := provider.Discover(context.Background(), testRepoConfig(repo.name))
+
+	var repoErrs discovery.RepositoryErrors
+	require.ErrorAs(t, err, &repoErrs)
+	assert.Contains(t, fmt.Sprint(repoErrs[repo.name]), "no network-configs directory")
+}
+
+func TestDiscover_CustomRefAndLayout(t *testing.T) {
+	repo := newFakeRepo("ethpandaops/other-devnets", map[string]string{
+		"configs/devnet-1/metadata/config.yaml":         "DEPOSIT_CHAIN_ID: 7001\n",
+		"configs/devnet-2/metadata/config.yaml":         "DEPOSIT_CHAIN_ID: 7002\n",
+		"deploy/devnet-1/values.yaml":                   "domain: devnet-1.example.com\nconfig:\n  files: []\n",
+		"deploy-archive/devnet-2/values.yaml":           "config:\n  files: []\n",
+		"inventory/devnet-1/group_vars/dns_server.yaml": "dns: true\n",
+		// Files at the default layout are ignored.
+		"kubernetes-archive/devnet-1/config/values.yaml": "config:\n  files: []\n",
+	})
+	provider := newFakeRepoProvider(t, repo)
+	provider.probeClient = &http.Client{Transport: &probeTransport{}}
+
+	config := discovery.Config{}
+	config.GitHub.Repositories = []discovery.GitHubRepositoryConfig{{
+		Name: repo.name,
+		Ref:  "v1.0.0",
+		Paths: discovery.RepositoryPaths{
+			NetworkConfigs: "configs",
+			Active:         "deploy/{network}",
+			Archived:       "deploy-archive/{network}",
+			ValuesFile:     "deploy/{network}/values.yaml",
+			Inventories:    "inventory/{network}",
+		},
+	}}
+
+	networks, err := provider.Discover(context.Background(), config)
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+
+	assert.Equal(t, "active", networks["devnet-1"].Status)
+	assert.Equal(t, uint64(7001), networks["devnet-1"].ChainID)
+	assert.True(t, networks["devnet-1"].SelfHostedDNS)
+	assert.Equal(t, "https://github.com/ethpandaops/other-devnets/tree/v1.0.0/configs/devnet-1", networks["devnet-1"].URL)
+
+	// The spec is at the same ref and layout as the network.
+	require.NotNil(t, networks["devnet-1"].ServiceURLs)
+	assert.Equal(t, "https://github.com/ethpandaops/other-devnets/tree/v1.0.0/configs/devnet-1/metadata", networks["devnet-1"].ServiceURLs.DevnetSpec)
+	assert.Equal(t, "inactive", networks["devnet-2"].Status)
+}
@@ -6,10 +6,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethpandaops/cartographoor/pkg/chainclock"
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
 	"github.com/sirupsen/logrus"
 )
 
+func init() {
+	discovery.RegisterProviderFactory("static", func(log *logrus.Logger, _ discovery.ProviderDeps, raw map[string]any) (discovery.Provider, error) {
+		// The provider has no options of its own yet, the networks are in
+		// discovery.static. Decoding still rejects unknown keys.
+		var config struct{}
+		if err := discovery.DecodeProviderConfig(raw, &config); err != nil {
+			return nil, err
+		}
+
+		return NewProvider(log)
+	})
+}
+
 // Provider implements the discovery.Provider interface for static networks.
 type Provider struct {
 	log *logrus.Logger
@@ -85,22 +99,19 @@ func (p *Provider) Discover(ctx context.Context, config discovery.Config) (map[s
 			}
 		}
 
-		// Get timing parameters with defaults
-		slotsPerEpoch := staticNet.SlotsPerEpoch
-		if slotsPerEpoch == 0 {
-			slotsPerEpoch = 32 // Default mainnet preset
-		}
+		// The slot timing, which can change at forks
+		clock, err := chainclock.New(staticNet.GenesisTime, p.timingSegments(staticNet)...)
+		if err != nil {
+			p.log.WithError(err).WithField("network", staticNet.Name).Error("Invalid slot timing for static network")
 
-		slotDurationSeconds := staticNet.SlotDurationSeconds
-		if slotDurationSeconds == 0 {
-			slotDurationSeconds = 12 // Default mainnet preset
+			continue
 		}
 
 		// Calculate timestamps for consensus forks
-		forks := p.calculateForkTimestamps(staticNet.Forks, staticNet.GenesisTime, slotsPerEpoch, slotDurationSeconds)
+		forks := p.calculateForkTimestamps(staticNet.Forks, clock)
 
 		// Calculate timestamps for blob schedule
-		blobSchedule := p.calculateBlobScheduleTimestamps(staticNet.BlobSchedule, staticNet.GenesisTime, slotsPerEpoch, slotDurationSeconds)
+		blobSchedule := p.calculateBlobScheduleTimestamps(staticNet.BlobSchedule, clock)
 
 		// Create network from configuration
 		network := discovery.Network{
@@ -108,7 +119,6 @@ func (p *Provider) Discover(ctx context.Context, config discovery.Config) (map[s
 			Description:  staticNet.Description,
 			Status:       "active", // All configured networks are active by definition
 			ChainID:      staticNet.ChainID,
-			LastUpdated:  time.Now(),
 			ServiceURLs:  serviceURLs,
 			Forks:        forks,
 			BlobSchedule: blobSchedule,
@@ -142,10 +152,44 @@ func (p *Provider) Discover(ctx context.Context, config discovery.Config) (map[s
 	return networks, nil
 }
 
-// calculateForkTimestamps calculates timestamps for consensus forks based on epoch and timing parameters.
+// timingSegments returns the slot timing of a static network: the timing at
+// genesis, defaulting to the mainnet preset, and its slot timing changes.
+func (p *Provider) timingSegments(staticNet discovery.StaticNetworkConfig) []chainclock.Segment {
+	segments := []chainclock.Segment{{
+		SlotsPerEpoch: staticNet.SlotsPerEpoch,
+		SlotDuration:  time.Duration(staticNet.SlotDurationSeconds) * time.Second,
+	}}
+
+	for _, timing := range staticNet.SlotTiming {
+		var (
+			fork discovery.ConsensusForkConfig
+			ok   bool
+		)
+
+		if staticNet.Forks != nil {
+			fork, ok = staticNet.Forks.Consensus[timing.Fork]
+		}
+
+		if !ok {
+			p.log.WithField("network", staticNet.Name).WithField("fork", timing.Fork).Warn("Skipping slot timing of unknown consensus fork")
+
+			continue
+		}
+
+		segments = append(segments, chainclock.Segment{
+			StartEpoch:    fork.Epoch,
+			SlotsPerEpoch: timing.SlotsPerEpoch,
+			SlotDuration:  time.Duration(timing.SlotDurationSeconds) * time.Second,
+		})
+	}
+
+	return segments
+}
+
+// calculateForkTimestamps calculates timestamps for consensus forks based on epoch and the chain clock.
 func (p *Provider) calculateForkTimestamps(
 	forks *discovery.ForksConfig,
-	genesisTime, slotsPerEpoch, slotDurationSeconds uint64,
+	clock *chainclock.Clock,
 ) *discovery.ForksConfig {
 	if forks == nil {
 		return nil
@@ -161,8 +205,8 @@ func (p *Provider) calculateForkTimestamps(
 		for name, fork := range forks.Consensus {
 			// Calculate timestamp if not already set
 			timestamp := fork.Timestamp
-			if timestamp == 0 && genesisTime > 0 {
-				timestamp = genesisTime + (fork.Epoch * slotsPerEpoch * slotDurationSeconds)
+			if timestamp == 0 && clock.GenesisTime() > 0 {
+				timestamp = clock.EpochTimestamp(fork.Epoch)
 			}
 
 			result.Consensus[name] = discovery.ConsensusForkConfig{
@@ -179,7 +223,7 @@ func (p *Provider) calculateForkTimestamps(
 // calculateBlobScheduleTimestamps calculates timestamps for blob schedule entries.
 func (p *Provider) calculateBlobScheduleTimestamps(
 	schedule []discovery.BlobSchedule,
-	genesisTime, slotsPerEpoch, slotDurationSeconds uint64,
+	clock *chainclock.Clock,
 ) []discovery.BlobSchedule {
 	if len(schedule) == 0 {
 		return nil
@@ -190,8 +234,8 @@ func (p *Provider) calculateBlobScheduleTimestamps(
 	for i, entry := range schedule {
 		// Calculate timestamp if not already set
 		timestamp := entry.Timestamp
-		if timestamp == 0 && genesisTime > 0 {
-			timestamp = genesisTime + (entry.Epoch * slotsPerEpoch * slotDurationSeconds)
+		if timestamp == 0 && clock.GenesisTime() > 0 {
+			timestamp = clock.EpochTimestamp(entry.Epoch)
 		}
 
 		result[i] = discovery.BlobSchedule{
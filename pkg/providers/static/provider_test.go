@@ -385,7 +385,7 @@ func TestProvider_Discover(t *testing.T) {
 	mainnet := networks["mainnet"]
 	assert.Equal(t, "mainnet", mainnet.Name)
 	assert.Equal(t, "Production Ethereum network", mainnet.Description)
-	assert.WithinDuration(t, time.Now(), mainnet.LastUpdated, 10*time.Second)
+	assert.Zero(t, mainnet.LastUpdated, "LastUpdated is set by the discovery service")
 	require.NotNil(t, mainnet.ServiceURLs)
 	assert.Equal(t, "https://ethstats.mainnet.ethpandaops.io", mainnet.ServiceURLs.Ethstats)
 	assert.Equal(t, "https://forkmon.mainnet.ethpandaops.io", mainnet.ServiceURLs.Forkmon)
@@ -397,7 +397,7 @@ func TestProvider_Discover(t *testing.T) {
 	sepolia := networks["sepolia"]
 	assert.Equal(t, "sepolia", sepolia.Name)
 	assert.Equal(t, "Smaller testnet for application development with controlled validator set.", sepolia.Description)
-	assert.WithinDuration(t, time.Now(), sepolia.LastUpdated, 10*time.Second)
+	assert.Zero(t, sepolia.LastUpdated, "LastUpdated is set by the discovery service")
 	require.NotNil(t, sepolia.ServiceURLs)
 	assert.Equal(t, "https://dora.sepolia.ethpandaops.io", sepolia.ServiceURLs.Dora)
 	assert.Equal(t, "https://dora.sepolia.ethpandaops.io", sepolia.ServiceURLs.BeaconExplorer)
@@ -408,7 +408,7 @@ func TestProvider_Discover(t *testing.T) {
 	hoodi := networks["hoodi"]
 	assert.Equal(t, "hoodi", hoodi.Name)
 	assert.Equal(t, "New public testnet (launched March 2025) designed for validator testing and protocol upgrades, replacing Holesky.", hoodi.Description)
-	assert.WithinDuration(t, time.Now(), hoodi.LastUpdated, 10*time.Second)
+	assert.Zero(t, hoodi.LastUpdated, "LastUpdated is set by the discovery service")
 	require.NotNil(t, hoodi.ServiceURLs)
 	assert.Equal(t, "https://dora.hoodi.ethpandaops.io", hoodi.ServiceURLs.Dora)
 	assert.Equal(t, "https://dora.hoodi.ethpandaops.io", hoodi.ServiceURLs.BeaconExplorer)
@@ -617,6 +617,43 @@ func TestProvider_TimestampCalculation(t *testing.T) {
 		assert.Equal(t, expectedTimestamp, fork.Timestamp)
 	})
 
+	t.Run("applies slot timing changes at forks", func(t *testing.T) {
+		genesisTime := uint64(1700000000)
+		gloasEpoch := uint64(100)
+		gloasTimestamp := genesisTime + (gloasEpoch * slotsPerEpoch * slotDurationSeconds)
+
+		config := discovery.Config{}
+		config.Static.Networks = []discovery.StaticNetworkConfig{
+			{
+				Name:        "test-6s-slots",
+				Description: "Test network with 6 second slots from gloas",
+				ChainID:     9999,
+				GenesisTime: genesisTime,
+				ServiceURLs: map[string]string{"ethstats": "https://ethstats.test.io"},
+				Forks: &discovery.ForksConfig{
+					Consensus: map[string]discovery.ConsensusForkConfig{
+						"fulu":  {Epoch: 50},
+						"gloas": {Epoch: gloasEpoch},
+					},
+				},
+				BlobSchedule: []discovery.BlobSchedule{
+					{Epoch: 150, MaxBlobsPerBlock: 21},
+				},
+				SlotTiming: []discovery.StaticSlotTimingConfig{
+					{Fork: "gloas", SlotDurationSeconds: 6},
+				},
+			},
+		}
+
+		networks, err := provider.Discover(context.Background(), config)
+		require.NoError(t, err)
+
+		network := networks["test-6s-slots"]
+		assert.Equal(t, genesisTime+(50*slotsPerEpoch*slotDurationSeconds), network.Forks.Consensus["fulu"].Timestamp)
+		assert.Equal(t, gloasTimestamp, network.Forks.Consensus["gloas"].Timestamp)
+		assert.Equal(t, gloasTimestamp+(50*slotsPerEpoch*6), network.BlobSchedule[0].Timestamp)
+	})
+
 	t.Run("preserves existing timestamps", func(t *testing.T) {
 		genesisTime := uint64(1606824023)
 		epoch := uint64(100)
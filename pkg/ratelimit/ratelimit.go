@@ -0,0 +1,250 @@
+// Package ratelimit tracks the GitHub API rate limits reported on responses.
+// It decides when a rate-limited request should be retried and when the budget
+// is low enough to skip low-priority work.
+package ratelimit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLowBudget is the default number of remaining core requests below
+	// which the budget is low.
+	DefaultLowBudget = 500
+
+	// DefaultMaxRetries is the default number of retries of a rate-limited
+	// request.
+	DefaultMaxRetries = 3
+
+	// DefaultMaxWait is the default longest wait before retrying a
+	// rate-limited request.
+	DefaultMaxWait = 2 * time.Minute
+
+	// secondaryBackoff is the first wait after a secondary rate limit without
+	// a Retry-After header. GitHub asks to wait at least a minute.
+	secondaryBackoff = time.Minute
+
+	// coreResource is the rate-limit resource of most REST API calls.
+	coreResource = "core"
+)
+
+// Config represents the configuration for GitHub rate-limit handling.
+type Config struct {
+	// LowBudget is the number of remaining core requests below which
+	// low-priority work, such as client release lookups and hive checks, is
+	// skipped until the rate-limit window resets. 0 never skips it. Defaults
+	// to DefaultLowBudget if not set.
+	LowBudget *int `mapstructure:"lowBudget"`
+
+	// MaxRetries is the number of times a request is retried after a
+	// rate-limited 403 or 429 response. 0 disables retries. Defaults to
+	// DefaultMaxRetries if not set.
+	MaxRetries *int `mapstructure:"maxRetries"`
+
+	// MaxWait is the longest wait before a retry. Requests that would have to
+	// wait longer fail with the rate-limit response.
+	MaxWait time.Duration `mapstructure:"maxWait"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	setDefault(&c.LowBudget, DefaultLowBudget)
+	setDefault(&c.MaxRetries, DefaultMaxRetries)
+
+	if c.MaxWait == 0 {
+		c.MaxWait = DefaultMaxWait
+	}
+}
+
+// setDefault sets value to def if it is not set.
+func setDefault(value **int, def int) {
+	if *value == nil {
+		*value = &def
+	}
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	if c.LowBudget != nil && *c.LowBudget < 0 {
+		return fmt.Errorf("lowBudget must not be negative, got %d", *c.LowBudget)
+	}
+
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		return fmt.Errorf("maxRetries must not be negative, got %d", *c.MaxRetries)
+	}
+
+	if c.MaxWait < 0 {
+		return fmt.Errorf("maxWait must not be negative, got %s", c.MaxWait)
+	}
+
+	return nil
+}
+
+// Limit is the state of a rate-limit resource as of the last response.
+type Limit struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Tracker records the rate limits of responses. A nil Tracker records
+// nothing, never retries and never reports a low budget.
+type Tracker struct {
+	config Config
+	now    func() time.Time
+
+	mutex  sync.Mutex
+	limits map[string]Limit
+}
+
+// New creates a new tracker.
+func New(config Config) (*Tracker, error) {
+	config.SetDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate limit config: %w", err)
+	}
+
+	return &Tracker{
+		config: config,
+		now:    time.Now,
+		limits: make(map[string]Limit),
+	}, nil
+}
+
+// Observe records the rate-limit headers of a response.
+func (t *Tracker) Observe(header http.Header) {
+	if t == nil {
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	resource := header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = coreResource
+	}
+
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.limits[resource] = Limit{
+		Resource:  resource,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0).UTC(),
+	}
+}
+
+// Limits returns the last observed limits, sorted by resource.
+func (t *Tracker) Limits() []Limit {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	limits := make([]Limit, 0, len(t.limits))
+	for _, limit := range t.limits {
+		limits = append(limits, limit)
+	}
+
+	slices.SortFunc(limits, func(a, b Limit) int {
+		return strings.Compare(a.Resource, b.Resource)
+	})
+
+	return limits
+}
+
+// Low returns true if fewer than the configured low budget of core requests
+// remain in the current rate-limit window.
+func (t *Tracker) Low() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	core, ok := t.limits[coreResource]
+
+	return ok && core.Remaining < *t.config.LowBudget && t.now().Before(core.Reset)
+}
+
+// Backoff returns how long to wait before retrying a request that got resp on
+// its attempt-th try, counting from zero. It returns false if resp isn't
+// rate-limited, the retries are used up or the wait would be too long.
+func (t *Tracker) Backoff(resp *http.Response, attempt int) (time.Duration, bool) {
+	if t == nil || attempt >= *t.config.MaxRetries || !rateLimited(resp) {
+		return 0, false
+	}
+
+	var wait time.Duration
+
+	switch {
+	case resp.Header.Get("Retry-After") != "":
+		seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err != nil {
+			return 0, false
+		}
+
+		wait = time.Duration(seconds) * time.Second
+	case resp.Header.Get("X-RateLimit-Remaining") == "0":
+		// The primary rate limit is used up, wait for the window to reset.
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		wait = max(time.Unix(reset, 0).Sub(t.now())+time.Second, 0)
+	default:
+		wait = secondaryBackoff << attempt
+	}
+
+	if wait > t.config.MaxWait {
+		return 0, false
+	}
+
+	return wait, true
+}
+
+// rateLimited returns true if resp was rejected by a primary or secondary rate
+// limit. Other 403s, such as missing permissions, are not retried.
+func rateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return true
+		}
+
+		// Secondary rate limits are only told apart by the message.
+		original := resp.Body
+		body, err := io.ReadAll(io.LimitReader(original, 4096))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), original), original}
+
+		return err == nil && strings.Contains(strings.ToLower(string(body)), "rate limit")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,173 @@
+package ratelimit
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Unix(1700000000, 0)
+
+func newTracker(t *testing.T) *Tracker {
+	t.Helper()
+
+	tracker, err := New(Config{LowBudget: new(100)})
+	require.NoError(t, err)
+
+	tracker.now = func() time.Time { return now }
+
+	return tracker
+}
+
+func rateLimitHeader(resource string, remaining int, reset time.Time) http.Header {
+	header := make(http.Header)
+	header.Set("X-RateLimit-Resource", resource)
+	header.Set("X-RateLimit-Limit", "5000")
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+	return header
+}
+
+func response(status int, header http.Header, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestTracker_Limits(t *testing.T) {
+	tracker := newTracker(t)
+
+	tracker.Observe(rateLimitHeader("search", 29, now.Add(time.Minute)))
+	tracker.Observe(rateLimitHeader("core", 4000, now.Add(time.Hour)))
+	tracker.Observe(rateLimitHeader("core", 3999, now.Add(time.Hour)))
+	tracker.Observe(http.Header{})
+
+	assert.Equal(t, []Limit{
+		{Resource: "core", Limit: 5000, Remaining: 3999, Reset: now.Add(time.Hour).UTC()},
+		{Resource: "search", Limit: 5000, Remaining: 29, Reset: now.Add(time.Minute).UTC()},
+	}, tracker.Limits())
+}
+
+func TestTracker_Low(t *testing.T) {
+	tracker := newTracker(t)
+	assert.False(t, tracker.Low(), "unknown budget")
+
+	tracker.Observe(rateLimitHeader("core", 100, now.Add(time.Hour)))
+	assert.False(t, tracker.Low())
+
+	tracker.Observe(rateLimitHeader("search", 0, now.Add(time.Hour)))
+	assert.False(t, tracker.Low(), "only the core budget counts")
+
+	tracker.Observe(rateLimitHeader("core", 99, now.Add(time.Hour)))
+	assert.True(t, tracker.Low())
+
+	tracker.Observe(rateLimitHeader("core", 99, now.Add(-time.Second)))
+	assert.False(t, tracker.Low(), "window has reset")
+
+	var nilTracker *Tracker
+	assert.False(t, nilTracker.Low())
+}
+
+func TestTracker_ZeroConfig(t *testing.T) {
+	tracker, err := New(Config{LowBudget: new(0), MaxRetries: new(0)})
+	require.NoError(t, err)
+
+	tracker.now = func() time.Time { return now }
+
+	tracker.Observe(rateLimitHeader("core", 0, now.Add(time.Hour)))
+	assert.False(t, tracker.Low(), "a zero low budget never skips work")
+
+	_, retry := tracker.Backoff(response(http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}}, ""), 0)
+	assert.False(t, retry, "zero max retries disables retries")
+
+	_, err = New(Config{MaxRetries: new(-1)})
+	require.ErrorContains(t, err, "maxRetries must not be negative")
+}
+
+func TestTracker_Backoff(t *testing.T) {
+	retryAfter := http.Header{"Retry-After": {"30"}}
+
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		attempt int
+		wait    time.Duration
+		retry   bool
+	}{
+		{
+			name:  "success",
+			resp:  response(http.StatusOK, rateLimitHeader("core", 0, now.Add(time.Minute)), ""),
+			retry: false,
+		},
+		{
+			name:  "retry after",
+			resp:  response(http.StatusTooManyRequests, retryAfter, ""),
+			wait:  30 * time.Second,
+			retry: true,
+		},
+		{
+			name:  "primary limit",
+			resp:  response(http.StatusForbidden, rateLimitHeader("core", 0, now.Add(time.Minute)), ""),
+			wait:  time.Minute + time.Second,
+			retry: true,
+		},
+		{
+			name:  "primary limit resets too late",
+			resp:  response(http.StatusForbidden, rateLimitHeader("core", 0, now.Add(time.Hour)), ""),
+			retry: false,
+		},
+		{
+			name:  "secondary limit",
+			resp:  response(http.StatusForbidden, rateLimitHeader("core", 10, now.Add(time.Hour)), `{"message":"You have exceeded a secondary rate limit."}`),
+			wait:  time.Minute,
+			retry: true,
+		},
+		{
+			name:    "secondary limit backs off exponentially",
+			resp:    response(http.StatusForbidden, rateLimitHeader("core", 10, now.Add(time.Hour)), `{"message":"You have exceeded a secondary rate limit."}`),
+			attempt: 1,
+			wait:    2 * time.Minute,
+			retry:   true,
+		},
+		{
+			name:  "forbidden",
+			resp:  response(http.StatusForbidden, rateLimitHeader("core", 10, now.Add(time.Hour)), `{"message":"Resource not accessible by integration"}`),
+			retry: false,
+		},
+		{
+			name:    "retries used up",
+			resp:    response(http.StatusTooManyRequests, retryAfter, ""),
+			attempt: DefaultMaxRetries,
+			retry:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retry := newTracker(t).Backoff(tt.resp, tt.attempt)
+			assert.Equal(t, tt.retry, retry)
+			assert.Equal(t, tt.wait, wait)
+		})
+	}
+}
+
+func TestTracker_BackoffKeepsBody(t *testing.T) {
+	body := `{"message":"Resource not accessible by integration"}`
+	resp := response(http.StatusForbidden, http.Header{}, body)
+
+	_, retry := newTracker(t).Backoff(resp, 0)
+	assert.False(t, retry)
+
+	read, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(read))
+}
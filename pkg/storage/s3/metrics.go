@@ -0,0 +1,39 @@
+package s3
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
+)
+
+var (
+	uploadDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "s3",
+		Name:      "upload_duration_seconds",
+		Help:      "Duration of S3 uploads by key.",
+		Buckets:   metrics.DefBuckets,
+	}, []string{"key"})
+
+	uploads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "s3",
+		Name:      "uploads_total",
+		Help:      "S3 uploads by key and status (success or failure).",
+	}, []string{"key", "status"})
+)
+
+// observeUpload records the outcome of an upload that started at start.
+func observeUpload(key string, start time.Time, err error) {
+	uploadDuration.WithLabelValues(key).Observe(time.Since(start).Seconds())
+
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+
+	uploads.WithLabelValues(key, status).Inc()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 type Config struct {
 	BucketName           string        `mapstructure:"bucketName"`
 	Key                  string        `mapstructure:"key"`
+	SchemaKey            string        `mapstructure:"schemaKey"`
 	Region               string        `mapstructure:"region"`
 	Endpoint             string        `mapstructure:"endpoint"`
 	AccessKey            string        `mapstructure:"accessKey"`
@@ -49,6 +51,10 @@ func NewProvider(log *logrus.Logger, cfg Config) (*Provider, error) {
 		cfg.Key = "networks.json"
 	}
 
+	if cfg.SchemaKey == "" {
+		cfg.SchemaKey = "networks.schema.json"
+	}
+
 	if cfg.ContentType == "" {
 		cfg.ContentType = "application/json"
 	}
@@ -71,6 +77,17 @@ func NewProvider(log *logrus.Logger, cfg Config) (*Provider, error) {
 	}, nil
 }
 
+// Key returns the S3 key the discovery result is uploaded to.
+func (p *Provider) Key() string {
+	return p.config.Key
+}
+
+// SchemaKey returns the S3 key the JSON Schema of the discovery result is
+// uploaded to.
+func (p *Provider) SchemaKey() string {
+	return p.config.SchemaKey
+}
+
 // Initialize sets up the S3 client.
 func (p *Provider) Initialize(ctx context.Context) error {
 	p.log.WithFields(logrus.Fields{
@@ -148,6 +165,11 @@ func (p *Provider) Upload(ctx context.Context, result discovery.Result) error {
 		return fmt.Errorf("failed to marshal discovery result: %w", err)
 	}
 
+	// Never publish a result that consumers can't parse against the schema.
+	if err := discovery.ValidateResultJSON(data); err != nil {
+		return fmt.Errorf("discovery result failed schema validation: %w", err)
+	}
+
 	// Create S3 put object input
 	input := &s3.PutObjectInput{
 		Bucket:      aws.String(p.config.BucketName),
@@ -168,7 +190,12 @@ func (p *Provider) Upload(ctx context.Context, result discovery.Result) error {
 		"acl":    p.config.ACL,
 	}).Info("Uploading networks to S3")
 
-	if _, err = p.client.PutObject(ctx, input); err != nil {
+	start := time.Now()
+
+	_, err = p.client.PutObject(ctx, input)
+	observeUpload(p.config.Key, start, err)
+
+	if err != nil {
 		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
 
@@ -204,7 +231,7 @@ func (p *Provider) Download(ctx context.Context, key string) ([]byte, error) {
 
 	for attempt := 0; attempt <= p.config.MaxRetries; attempt++ {
 		result, err = p.client.GetObject(ctx, input)
-		if err == nil {
+		if err == nil || IsNotFound(err) {
 			break
 		}
 
@@ -238,6 +265,13 @@ func (p *Provider) Download(ctx context.Context, key string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// IsNotFound returns true if the error is caused by a missing S3 object.
+func IsNotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+
+	return errors.As(err, &noSuchKey)
+}
+
 // UploadRaw uploads raw data to S3 with a specific key.
 func (p *Provider) UploadRaw(ctx context.Context, key string, data []byte, contentType string) error {
 	if p.client == nil {
@@ -267,7 +301,12 @@ func (p *Provider) UploadRaw(ctx context.Context, key string, data []byte, conte
 		"acl":    p.config.ACL,
 	}).Debug("Uploading raw data to S3")
 
-	if _, err := p.client.PutObject(ctx, input); err != nil {
+	start := time.Now()
+
+	_, err := p.client.PutObject(ctx, input)
+	observeUpload(key, start, err)
+
+	if err != nil {
 		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
 
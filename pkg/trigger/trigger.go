@@ -0,0 +1,201 @@
+// Package trigger receives on-demand discovery triggers over HTTP: an
+// authenticated POST endpoint and a GitHub push webhook that only rescans the
+// repository that changed.
+package trigger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxBodySize limits request bodies, GitHub push payloads are at most 25MB but
+// only the repository name is needed.
+const maxBodySize = 25 << 20
+
+// Config represents the configuration for discovery triggers.
+type Config struct {
+	// Token enables POST /trigger, authenticated with "Authorization: Bearer <token>".
+	Token string `mapstructure:"token"`
+
+	// GitHubWebhookSecret enables POST /webhooks/github, verified against the
+	// X-Hub-Signature-256 header.
+	GitHubWebhookSecret string `mapstructure:"githubWebhookSecret"`
+}
+
+// Enabled returns true if any HTTP trigger is configured.
+func (c *Config) Enabled() bool {
+	return c.Token != "" || c.GitHubWebhookSecret != ""
+}
+
+// Triggerer runs discovery on demand, it is implemented by discovery.Service.
+type Triggerer interface {
+	// Trigger requests a discovery run, scoped to repositories if any are
+	// given. It returns false if none of the repositories are configured.
+	Trigger(reason string, repositories ...string) bool
+}
+
+// Handler serves the trigger endpoints.
+type Handler struct {
+	log       logrus.FieldLogger
+	config    Config
+	triggerer Triggerer
+}
+
+// NewHandler creates a new trigger handler.
+func NewHandler(log logrus.FieldLogger, config Config, triggerer Triggerer) *Handler {
+	return &Handler{
+		log:       log.WithField("module", "trigger"),
+		config:    config,
+		triggerer: triggerer,
+	}
+}
+
+// Routes returns the configured endpoints by pattern.
+func (h *Handler) Routes() map[string]http.Handler {
+	routes := make(map[string]http.Handler)
+
+	if h.config.Token != "" {
+		routes["POST /trigger"] = http.HandlerFunc(h.handleTrigger)
+	}
+
+	if h.config.GitHubWebhookSecret != "" {
+		routes["POST /webhooks/github"] = http.HandlerFunc(h.handleGitHubWebhook)
+	}
+
+	return routes
+}
+
+// triggerRequest is the optional body of POST /trigger.
+type triggerRequest struct {
+	Repositories []string `json:"repositories"`
+}
+
+// triggerResponse is the body of all trigger responses.
+type triggerResponse struct {
+	Triggered    bool     `json:"triggered"`
+	Repositories []string `json:"repositories,omitempty"`
+	Message      string   `json:"message,omitempty"`
+}
+
+// handleTrigger triggers a full run, or a run scoped to the repositories in
+// the body.
+func (h *Handler) handleTrigger(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.config.Token)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, triggerResponse{Message: "invalid token"})
+
+		return
+	}
+
+	var req triggerRequest
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, triggerResponse{Message: "failed to read body"})
+
+		return
+	}
+
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, triggerResponse{Message: "invalid body: " + err.Error()})
+
+			return
+		}
+	}
+
+	h.trigger(w, "http", req.Repositories)
+}
+
+// pushEvent is the part of a GitHub push event payload that is needed.
+type pushEvent struct {
+	Repository struct {
+		FullName string `json:"full_name"` //nolint:tagliatelle // GitHub's payload format.
+	} `json:"repository"`
+}
+
+// handleGitHubWebhook triggers a run scoped to the repository of a push event.
+func (h *Handler) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, triggerResponse{Message: "failed to read body"})
+
+		return
+	}
+
+	if err := VerifySignature(h.config.GitHubWebhookSecret, body, r.Header.Get("X-Hub-Signature-256")); err != nil {
+		h.log.WithError(err).Warn("Rejected GitHub webhook")
+		writeJSON(w, http.StatusUnauthorized, triggerResponse{Message: err.Error()})
+
+		return
+	}
+
+	switch event := r.Header.Get("X-GitHub-Event"); event {
+	case "ping":
+		writeJSON(w, http.StatusOK, triggerResponse{Message: "pong"})
+	case "push":
+		var push pushEvent
+		if err := json.Unmarshal(body, &push); err != nil || push.Repository.FullName == "" {
+			writeJSON(w, http.StatusBadRequest, triggerResponse{Message: "invalid push event"})
+
+			return
+		}
+
+		h.trigger(w, "github-push", []string{push.Repository.FullName})
+	default:
+		writeJSON(w, http.StatusOK, triggerResponse{Message: "ignored event " + event})
+	}
+}
+
+// trigger requests a run and writes the response.
+func (h *Handler) trigger(w http.ResponseWriter, reason string, repositories []string) {
+	if !h.triggerer.Trigger(reason, repositories...) {
+		writeJSON(w, http.StatusOK, triggerResponse{
+			Repositories: repositories,
+			Message:      "repositories are not configured",
+		})
+
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, triggerResponse{Triggered: true, Repositories: repositories})
+}
+
+// VerifySignature checks a GitHub "sha256=<hex>" HMAC signature of body.
+func VerifySignature(secret string, body []byte, signature string) error {
+	hexSignature, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return errors.New("missing or malformed X-Hub-Signature-256 header")
+	}
+
+	got, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return errors.New("malformed X-Hub-Signature-256 header")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,154 @@
+package trigger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type trigger struct {
+	reason       string
+	repositories []string
+}
+
+// fakeTriggerer records triggers and only knows the org/devnets repository.
+type fakeTriggerer struct {
+	triggers []trigger
+}
+
+func (f *fakeTriggerer) Trigger(reason string, repositories ...string) bool {
+	for _, repo := range repositories {
+		if repo != "org/devnets" {
+			return false
+		}
+	}
+
+	f.triggers = append(f.triggers, trigger{reason: reason, repositories: repositories})
+
+	return true
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func serve(t *testing.T, config Config, req *http.Request) (*httptest.ResponseRecorder, *fakeTriggerer) {
+	t.Helper()
+
+	triggerer := &fakeTriggerer{}
+	mux := http.NewServeMux()
+
+	for pattern, handler := range NewHandler(logrus.New(), config, triggerer).Routes() {
+		mux.Handle(pattern, handler)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	return rec, triggerer
+}
+
+func TestHandleTrigger(t *testing.T) {
+	config := Config{Token: "secret-token"}
+
+	tests := []struct {
+		name   string
+		auth   string
+		body   string
+		status int
+		want   []trigger
+	}{
+		{name: "missing token", body: "", status: http.StatusUnauthorized},
+		{name: "wrong token", auth: "Bearer nope", status: http.StatusUnauthorized},
+		{name: "full run", auth: "Bearer secret-token", status: http.StatusAccepted, want: []trigger{{reason: "http"}}},
+		{
+			name:   "scoped run",
+			auth:   "Bearer secret-token",
+			body:   `{"repositories": ["org/devnets"]}`,
+			status: http.StatusAccepted,
+			want:   []trigger{{reason: "http", repositories: []string{"org/devnets"}}},
+		},
+		{name: "unknown repository", auth: "Bearer secret-token", body: `{"repositories": ["other/repo"]}`, status: http.StatusOK},
+		{name: "invalid body", auth: "Bearer secret-token", body: `{`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/trigger", strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+
+			rec, triggerer := serve(t, config, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.want, triggerer.triggers)
+		})
+	}
+}
+
+func TestHandleGitHubWebhook(t *testing.T) {
+	const secret = "webhook-secret"
+
+	push := `{"ref": "refs/heads/main", "repository": {"full_name": "org/devnets"}}`
+
+	tests := []struct {
+		name      string
+		event     string
+		body      string
+		signature string
+		status    int
+		want      []trigger
+	}{
+		{
+			name:      "push",
+			event:     "push",
+			body:      push,
+			signature: sign(secret, push),
+			status:    http.StatusAccepted,
+			want:      []trigger{{reason: "github-push", repositories: []string{"org/devnets"}}},
+		},
+		{name: "bad signature", event: "push", body: push, signature: sign("other", push), status: http.StatusUnauthorized},
+		{name: "missing signature", event: "push", body: push, status: http.StatusUnauthorized},
+		{name: "ping", event: "ping", body: `{}`, signature: sign(secret, `{}`), status: http.StatusOK},
+		{name: "other event", event: "issues", body: `{}`, signature: sign(secret, `{}`), status: http.StatusOK},
+		{
+			name:      "unconfigured repository",
+			event:     "push",
+			body:      `{"repository": {"full_name": "other/repo"}}`,
+			signature: sign(secret, `{"repository": {"full_name": "other/repo"}}`),
+			status:    http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(tt.body))
+			req.Header.Set("X-GitHub-Event", tt.event)
+
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+
+			rec, triggerer := serve(t, Config{GitHubWebhookSecret: secret}, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.want, triggerer.triggers)
+		})
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	assert.Empty(t, NewHandler(logrus.New(), Config{}, &fakeTriggerer{}).Routes())
+	assert.Len(t, NewHandler(logrus.New(), Config{Token: "t", GitHubWebhookSecret: "s"}, &fakeTriggerer{}).Routes(), 2)
+}
@@ -0,0 +1,161 @@
+// Package uploadguard decides whether a discovery result is safe to publish by
+// comparing it with the currently published result, so that a half-failed run
+// cannot overwrite networks.json with a fraction of its networks.
+package uploadguard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// Default thresholds, as a fraction of the published count.
+const (
+	DefaultMaxNetworkDrop       = 0.5
+	DefaultMaxActiveNetworkDrop = 0.5
+	DefaultMaxClientDrop        = 0.5
+)
+
+// Config represents the configuration for the upload guard.
+type Config struct {
+	// Enabled turns the guard on. Defaults to true.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// MaxNetworkDrop is the largest allowed drop in the number of networks, as
+	// a fraction (0-1) of the published count. 0 allows no drop at all.
+	// Defaults to DefaultMaxNetworkDrop if not set.
+	MaxNetworkDrop *float64 `mapstructure:"maxNetworkDrop"`
+
+	// MaxActiveNetworkDrop is the largest allowed drop in the number of active
+	// networks, as a fraction (0-1) of the published count. 0 allows no drop
+	// at all. Defaults to DefaultMaxActiveNetworkDrop if not set.
+	MaxActiveNetworkDrop *float64 `mapstructure:"maxActiveNetworkDrop"`
+
+	// MaxClientDrop is the largest allowed drop in the number of clients, as a
+	// fraction (0-1) of the published count. 0 allows no drop at all. Defaults
+	// to DefaultMaxClientDrop if not set.
+	MaxClientDrop *float64 `mapstructure:"maxClientDrop"`
+
+	// RequiredNetworks must be present in every uploaded result.
+	RequiredNetworks []string `mapstructure:"requiredNetworks"`
+}
+
+// SetDefaults applies default values to the config if not set.
+func (c *Config) SetDefaults() {
+	if c.Enabled == nil {
+		enabled := true
+		c.Enabled = &enabled
+	}
+
+	setDefault(&c.MaxNetworkDrop, DefaultMaxNetworkDrop)
+	setDefault(&c.MaxActiveNetworkDrop, DefaultMaxActiveNetworkDrop)
+	setDefault(&c.MaxClientDrop, DefaultMaxClientDrop)
+}
+
+// setDefault sets value to def if it is not set.
+func setDefault(value **float64, def float64) {
+	if *value == nil {
+		*value = &def
+	}
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	for name, value := range map[string]*float64{
+		"maxNetworkDrop":       c.MaxNetworkDrop,
+		"maxActiveNetworkDrop": c.MaxActiveNetworkDrop,
+		"maxClientDrop":        c.MaxClientDrop,
+	} {
+		if value != nil && (*value < 0 || *value > 1) {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", name, *value)
+		}
+	}
+
+	return nil
+}
+
+// ViolationError is returned when a result violates the upload policy.
+type ViolationError struct {
+	Violations []string
+}
+
+// Error implements the error interface.
+func (e *ViolationError) Error() string {
+	return "upload guard blocked upload: " + strings.Join(e.Violations, "; ")
+}
+
+// Guard checks results against the upload policy.
+type Guard struct {
+	config Config
+}
+
+// New creates a new upload guard.
+func New(config Config) (*Guard, error) {
+	config.SetDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid upload guard config: %w", err)
+	}
+
+	return &Guard{config: config}, nil
+}
+
+// Check returns a *ViolationError if the current result must not replace the
+// published one. published is nil if nothing has been published yet, in which
+// case only the required networks are checked.
+func (g *Guard) Check(published *discovery.Result, current discovery.Result) error {
+	if !*g.config.Enabled {
+		return nil
+	}
+
+	violations := make([]string, 0)
+
+	for _, name := range g.config.RequiredNetworks {
+		if _, ok := current.Networks[name]; !ok {
+			violations = append(violations, fmt.Sprintf("required network %s is missing", name))
+		}
+	}
+
+	if published != nil {
+		violations = appendDrop(violations, "networks", len(published.Networks), len(current.Networks), *g.config.MaxNetworkDrop)
+		violations = appendDrop(violations, "active networks", countActive(published.Networks), countActive(current.Networks), *g.config.MaxActiveNetworkDrop)
+		violations = appendDrop(violations, "clients", len(published.Clients), len(current.Clients), *g.config.MaxClientDrop)
+	}
+
+	if len(violations) > 0 {
+		return &ViolationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// appendDrop appends a violation if the count dropped by more than maxDrop.
+func appendDrop(violations []string, what string, previous, current int, maxDrop float64) []string {
+	if previous == 0 || current >= previous {
+		return violations
+	}
+
+	drop := float64(previous-current) / float64(previous)
+	if drop <= maxDrop {
+		return violations
+	}
+
+	return append(violations, fmt.Sprintf(
+		"%s dropped from %d to %d (%.0f%%, max %.0f%%)",
+		what, previous, current, drop*100, maxDrop*100,
+	))
+}
+
+// countActive returns the number of active networks.
+func countActive(networks map[string]discovery.Network) int {
+	active := 0
+
+	for _, network := range networks {
+		if network.Status == "active" {
+			active++
+		}
+	}
+
+	return active
+}
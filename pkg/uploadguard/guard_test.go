@@ -0,0 +1,141 @@
+package uploadguard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+// makeResult builds a result with the given number of active and inactive
+// networks and clients. Networks are named net-0, net-1, ...
+func makeResult(active, inactive, clients int) discovery.Result {
+	result := discovery.Result{
+		Networks: make(map[string]discovery.Network),
+		Clients:  make(map[string]discovery.ClientInfo),
+	}
+
+	for i := 0; i < active+inactive; i++ {
+		status := "active"
+		if i >= active {
+			status = "inactive"
+		}
+
+		name := fmt.Sprintf("net-%d", i)
+		result.Networks[name] = discovery.Network{Name: name, Status: status}
+	}
+
+	for i := 0; i < clients; i++ {
+		name := fmt.Sprintf("client-%d", i)
+		result.Clients[name] = discovery.ClientInfo{Name: name}
+	}
+
+	return result
+}
+
+func TestGuard_Check(t *testing.T) {
+	published := makeResult(8, 2, 10)
+
+	zero, tenPercent := 0.0, 0.1
+
+	tests := []struct {
+		name       string
+		config     Config
+		published  *discovery.Result
+		current    discovery.Result
+		violations []string
+	}{
+		{
+			name:      "unchanged",
+			published: &published,
+			current:   published,
+		},
+		{
+			name:      "drop within threshold",
+			published: &published,
+			current:   makeResult(4, 1, 5),
+		},
+		{
+			name:      "growth",
+			published: &published,
+			current:   makeResult(20, 5, 30),
+		},
+		{
+			name:      "networks dropped",
+			published: &published,
+			current:   makeResult(2, 0, 10),
+			violations: []string{
+				"networks dropped from 10 to 2 (80%, max 50%)",
+				"active networks dropped from 8 to 2 (75%, max 50%)",
+			},
+		},
+		{
+			name:       "clients dropped",
+			published:  &published,
+			current:    makeResult(8, 2, 1),
+			violations: []string{"clients dropped from 10 to 1 (90%, max 50%)"},
+		},
+		{
+			name:       "custom threshold",
+			config:     Config{MaxClientDrop: &tenPercent},
+			published:  &published,
+			current:    makeResult(8, 2, 8),
+			violations: []string{"clients dropped from 10 to 8 (20%, max 10%)"},
+		},
+		{
+			name:       "no drop allowed",
+			config:     Config{MaxNetworkDrop: &zero},
+			published:  &published,
+			current:    makeResult(7, 2, 10),
+			violations: []string{"networks dropped from 10 to 9 (10%, max 0%)"},
+		},
+		{
+			name:       "required network missing",
+			config:     Config{RequiredNetworks: []string{"net-0", "mainnet"}},
+			published:  &published,
+			current:    published,
+			violations: []string{"required network mainnet is missing"},
+		},
+		{
+			name:       "nothing published only checks required networks",
+			config:     Config{RequiredNetworks: []string{"mainnet"}},
+			current:    makeResult(1, 0, 0),
+			violations: []string{"required network mainnet is missing"},
+		},
+		{
+			name:      "disabled",
+			config:    Config{Enabled: new(bool), RequiredNetworks: []string{"mainnet"}},
+			published: &published,
+			current:   makeResult(0, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard, err := New(tt.config)
+			require.NoError(t, err)
+
+			err = guard.Check(tt.published, tt.current)
+			if len(tt.violations) == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var violationErr *ViolationError
+
+			require.ErrorAs(t, err, &violationErr)
+			assert.Equal(t, tt.violations, violationErr.Violations)
+		})
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tooLarge := 1.5
+
+	_, err := New(Config{MaxNetworkDrop: &tooLarge})
+	assert.ErrorContains(t, err, "maxNetworkDrop must be between 0 and 1")
+}
@@ -6,6 +6,11 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/ethpandaops/cartographoor/pkg/githubapi"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
 )
 
 // Fetcher handles downloading inventory files from GitHub.
@@ -13,12 +18,12 @@ type Fetcher struct {
 	httpClient *http.Client
 }
 
-// NewFetcher creates a new Fetcher instance.
-func NewFetcher() *Fetcher {
+// NewFetcher creates a new Fetcher instance. Inventory files on GitHub are
+// fetched with auth, so private repositories can be read, and revalidated
+// through cache; both may be nil.
+func NewFetcher(auth oauth2.TokenSource, cache *httpcache.Cache) *Fetcher {
 	return &Fetcher{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient: githubapi.NewContentHTTPClient(auth, cache, 30*time.Second),
 	}
 }
 
@@ -47,9 +52,10 @@ func (f *Fetcher) FetchInventoryFile(ctx context.Context, url string) ([]byte, e
 	return data, nil
 }
 
-// BuildInventoryURLs constructs URLs for standard inventory files for a network.
-func (f *Fetcher) BuildInventoryURLs(repo, network string) []string {
-	baseURL := fmt.Sprintf("https://raw.githubusercontent.com/%s/master/ansible/inventories/%s", repo, network)
+// BuildInventoryURLs constructs URLs for standard inventory files in the
+// inventory directory dir of a network, at ref of repo.
+func (f *Fetcher) BuildInventoryURLs(repo, ref, dir string) []string {
+	baseURL := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", repo, ref, dir)
 
 	return []string{
 		fmt.Sprintf("%s/inventory.ini", baseURL),
@@ -5,28 +5,54 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"github.com/ethpandaops/cartographoor/pkg/discovery"
+	"github.com/ethpandaops/cartographoor/pkg/httpcache"
+	"github.com/ethpandaops/cartographoor/pkg/metrics"
 	"github.com/ethpandaops/cartographoor/pkg/storage/s3"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
 	"golang.org/x/sync/semaphore"
 )
 
+// defaultInventoryRef is the ref inventories are read at if the repository of
+// a network isn't configured. raw.githubusercontent.com resolves it to the
+// default branch.
+const defaultInventoryRef = "HEAD"
+
 // Service handles the generation and upload of validator ranges.
 type Service struct {
 	fetcher   *Fetcher
 	s3Storage *s3.Provider
 	config    *Config
-	logger    *logrus.Entry
+	// repositories are the discovery repositories by lowercase name, whose ref
+	// and layout locate the inventories of their networks.
+	repositories map[string]discovery.GitHubRepositoryConfig
+	logger       *logrus.Entry
 }
 
-// NewService creates a new validator ranges service.
-func NewService(s3Storage *s3.Provider, config *Config, logger *logrus.Logger) *Service {
+// NewService creates a new validator ranges service. repositories are the
+// GitHub repositories configured for discovery. auth and cache may be nil.
+func NewService(
+	s3Storage *s3.Provider,
+	config *Config,
+	repositories []discovery.GitHubRepositoryConfig,
+	logger *logrus.Logger,
+	auth oauth2.TokenSource,
+	cache *httpcache.Cache,
+) *Service {
+	repos := make(map[string]discovery.GitHubRepositoryConfig, len(repositories))
+	for _, repo := range repositories {
+		repos[strings.ToLower(repo.Name)] = repo
+	}
+
 	return &Service{
-		fetcher:   NewFetcher(),
-		s3Storage: s3Storage,
-		config:    config,
-		logger:    logger.WithField("module", "validator_ranges"),
+		fetcher:      NewFetcher(auth, cache),
+		s3Storage:    s3Storage,
+		config:       config,
+		repositories: repos,
+		logger:       logger.WithField("module", "validator_ranges"),
 	}
 }
 
@@ -37,6 +63,8 @@ func (s *Service) GenerateValidatorRanges(ctx context.Context, networks map[stri
 	// Use semaphore to limit concurrency to 5 networks at a time
 	sem := semaphore.NewWeighted(5)
 
+	var succeeded, failed atomic.Int64
+
 	for name, network := range networks {
 		if err := sem.Acquire(ctx, 1); err != nil {
 			return fmt.Errorf("failed to acquire semaphore: %w", err)
@@ -46,11 +74,16 @@ func (s *Service) GenerateValidatorRanges(ctx context.Context, networks map[stri
 			defer sem.Release(1)
 
 			if err := s.processNetwork(ctx, networkName, net); err != nil {
+				failed.Add(1)
 				s.logger.WithFields(logrus.Fields{
 					"network": networkName,
 					"error":   err,
 				}).Error("Failed to process network")
+
+				return
 			}
+
+			succeeded.Add(1)
 		}(name, network)
 	}
 
@@ -61,6 +94,8 @@ func (s *Service) GenerateValidatorRanges(ctx context.Context, networks map[stri
 
 	sem.Release(5)
 
+	metrics.ObserveGeneratedItems(metrics.GeneratorValidatorRanges, int(succeeded.Load()), int(failed.Load()))
+
 	s.logger.Info("Validator ranges generation completed")
 
 	return nil
@@ -130,20 +165,9 @@ func (s *Service) fetchEthpandaopsRanges(ctx context.Context, networkName string
 		repo = "ethpandaops/ansible"
 	}
 
-	// Strip repository-specific prefixes from network name for inventory path
-	inventoryName := networkName
-	// Common prefixes to strip (e.g., "fusaka-devnet-5" -> "devnet-5")
-	prefixes := []string{"fusaka-", "pectra-", "dencun-", "eof-", "verkle-"}
-	for _, prefix := range prefixes {
-		if after, ok := strings.CutPrefix(networkName, prefix); ok {
-			inventoryName = after
-
-			break
-		}
-	}
-
 	// Build inventory URLs
-	urls := s.fetcher.BuildInventoryURLs(repo, inventoryName)
+	ref, dir := s.inventoryLocation(repo, networkName, network)
+	urls := s.fetcher.BuildInventoryURLs(repo, ref, dir)
 
 	// Fetch inventory files
 	contents, successfulURLs, err := s.fetcher.FetchMultiple(ctx, urls)
@@ -176,6 +200,35 @@ func (s *Service) fetchEthpandaopsRanges(ctx context.Context, networkName string
 	return AggregateRanges(allRanges), nil
 }
 
+// inventoryLocation returns the ref and directory of the inventories of a
+// network in repo, following the discovery config of the repository.
+func (s *Service) inventoryLocation(repo, networkName string, network discovery.Network) (string, string) {
+	repoConfig, configured := s.repositories[strings.ToLower(repo)]
+
+	ref := repoConfig.Ref
+	if ref == "" {
+		ref = defaultInventoryRef
+	}
+
+	// Networks of a configured repository carry their unprefixed name.
+	inventoryName := network.Name
+	if !configured || inventoryName == "" {
+		// Strip repository-specific prefixes from network name for inventory path
+		inventoryName = networkName
+		// Common prefixes to strip (e.g., "fusaka-devnet-5" -> "devnet-5")
+		prefixes := []string{"fusaka-", "pectra-", "dencun-", "eof-", "verkle-"}
+		for _, prefix := range prefixes {
+			if after, ok := strings.CutPrefix(networkName, prefix); ok {
+				inventoryName = after
+
+				break
+			}
+		}
+	}
+
+	return ref, discovery.ForNetwork(repoConfig.Paths.WithDefaults().Inventories, inventoryName)
+}
+
 // fetchAdditionalRanges fetches validator ranges from additional configured sources.
 func (s *Service) fetchAdditionalRanges(ctx context.Context, networkName string) ([]*ValidatorRanges, error) {
 	if s.config == nil || s.config.AdditionalSources == nil {
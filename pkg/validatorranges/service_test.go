@@ -0,0 +1,63 @@
+package validatorranges
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/cartographoor/pkg/discovery"
+)
+
+func TestInventoryLocation(t *testing.T) {
+	service := NewService(nil, nil, []discovery.GitHubRepositoryConfig{
+		{Name: "ethpandaops/fusaka-devnets", NamePrefix: "fusaka-"},
+		{
+			Name:  "org/custom-devnets",
+			Ref:   "main",
+			Paths: discovery.RepositoryPaths{Inventories: "inventory/{network}/hosts"},
+		},
+	}, logrus.New(), nil, nil)
+
+	tests := []struct {
+		name        string
+		repo        string
+		networkName string
+		network     discovery.Network
+		wantRef     string
+		wantDir     string
+	}{
+		{
+			name:        "default layout",
+			repo:        "ethpandaops/fusaka-devnets",
+			networkName: "fusaka-devnet-5",
+			network:     discovery.Network{Name: "devnet-5"},
+			wantRef:     "HEAD",
+			wantDir:     "ansible/inventories/devnet-5",
+		},
+		{
+			name:        "custom ref and layout",
+			repo:        "Org/Custom-Devnets",
+			networkName: "devnet-1",
+			network:     discovery.Network{Name: "devnet-1"},
+			wantRef:     "main",
+			wantDir:     "inventory/devnet-1/hosts",
+		},
+		{
+			name:        "unconfigured repository strips known prefixes",
+			repo:        "ethpandaops/pectra-devnets",
+			networkName: "pectra-devnet-6",
+			network:     discovery.Network{Name: "pectra-devnet-6"},
+			wantRef:     "HEAD",
+			wantDir:     "ansible/inventories/devnet-6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, dir := service.inventoryLocation(tt.repo, tt.networkName, tt.network)
+			if ref != tt.wantRef || dir != tt.wantDir {
+				t.Errorf("inventoryLocation() = %q, %q, want %q, %q", ref, dir, tt.wantRef, tt.wantDir)
+			}
+		})
+	}
+}